@@ -12,13 +12,27 @@ import (
 )
 
 func main() {
-	var outputFile string
+	var (
+		outputFile       string
+		packageName      string
+		topLevelRoutines bool
+		crlf             bool
+		checkedPointers  bool
+		isoFieldWidths   bool
+		rangeChecks      bool
+	)
 
 	flag.StringVar(&outputFile, "o", "", "if non-empty, where the output will be written to")
+	flag.StringVar(&packageName, "package", "main", "package name of the output; if not main, a library package without main function is generated")
+	flag.BoolVar(&topLevelRoutines, "toplevel", false, "if true, procedures and functions are generated as top-level functions")
+	flag.BoolVar(&crlf, "crlf", false, "if true, the generated program terminates lines with CR LF instead of LF")
+	flag.BoolVar(&checkedPointers, "checkptr", false, "if true, the generated program panics when a disposed pointer is dereferenced")
+	flag.BoolVar(&isoFieldWidths, "isowidths", false, "if true, the generated program writes integers and reals with ISO Pascal's conventional default field widths")
+	flag.BoolVar(&rangeChecks, "rangecheck", false, "if true, the generated program panics when a value that is out of range is read into a subrange variable")
 	flag.Parse()
 
 	if len(flag.Args()) == 0 {
-		fmt.Fprintf(os.Stdout, "usage: %s [-o output.go] file.pas", os.Args[0])
+		fmt.Fprintf(os.Stdout, "usage: %s [-o output.go] [-package name] [-toplevel] [-crlf] [-checkptr] [-isowidths] [-rangecheck] file.pas", os.Args[0])
 		os.Exit(1)
 	}
 
@@ -29,12 +43,29 @@ func main() {
 		log.Fatalf("Reading file %s failed: %v", sourceFile, err)
 	}
 
-	ast, err := parser.Parse(sourceFile, string(source))
+	ast, err := parser.Parse(sourceFile, string(source), parser.WithErrorSnippets())
 	if err != nil {
 		log.Fatalf("Parsing %s failed: %v", sourceFile, err)
 	}
 
-	goSource, err := pas2go.Transpile(ast)
+	opts := []pas2go.Option{pas2go.WithPackageName(packageName)}
+	if topLevelRoutines {
+		opts = append(opts, pas2go.WithTopLevelRoutines())
+	}
+	if crlf {
+		opts = append(opts, pas2go.WithLineTerminator("\r\n"))
+	}
+	if checkedPointers {
+		opts = append(opts, pas2go.WithCheckedPointers())
+	}
+	if isoFieldWidths {
+		opts = append(opts, pas2go.WithISOFieldWidths())
+	}
+	if rangeChecks {
+		opts = append(opts, pas2go.WithRangeChecks())
+	}
+
+	goSource, err := pas2go.Transpile(ast, opts...)
 	if err != nil {
 		log.Fatalf("Transpiling %s failed: %v", sourceFile, err)
 	}
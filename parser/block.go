@@ -195,7 +195,7 @@ func (b *Block) findType(name string) DataType {
 	return foundType
 }
 
-func (b *Block) findEnumValue(ident string) (idx int, typ DataType) {
+func (b *Block) findEnumValue(ident string) (value int, typ DataType) {
 	if b == nil {
 		return 0, nil
 	}
@@ -204,7 +204,7 @@ func (b *Block) findEnumValue(ident string) (idx int, typ DataType) {
 		if et, ok := v.Type.(*EnumType); ok {
 			for idx, enumIdent := range et.Identifiers {
 				if ident == enumIdent {
-					return idx, v.Type
+					return et.Value(idx), v.Type
 				}
 			}
 		}
@@ -214,7 +214,7 @@ func (b *Block) findEnumValue(ident string) (idx int, typ DataType) {
 		if et, ok := td.Type.(*EnumType); ok {
 			for idx, enumIdent := range et.Identifiers {
 				if ident == enumIdent {
-					return idx, td.Type
+					return et.Value(idx), td.Type
 				}
 			}
 		}
@@ -268,6 +268,17 @@ func (b *Block) isIdentifierUsed(name string) bool {
 	return false
 }
 
+// isIdentifierInScope returns true if the identifier is used in the block or any of its
+// enclosing blocks.
+func (b *Block) isIdentifierInScope(name string) bool {
+	for ; b != nil; b = b.Parent {
+		if b.isIdentifierUsed(name) {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *Block) addLabel(label string) error {
 	i, err := strconv.ParseInt(label, 10, 64)
 	if err != nil {
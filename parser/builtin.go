@@ -144,6 +144,82 @@ var builtinProcedures = []*Routine{
 			return nil, fmt.Errorf("reset: need exactly 1 argument of file type, got %s instead", exprs[0].Type().TypeString())
 		},
 	},
+	{
+		Name: "assign",
+		validator: func(exprs []Expression) (DataType, error) {
+			if len(exprs) != 2 {
+				return nil, fmt.Errorf("assign: need exactly 2 arguments of file and string type")
+			}
+
+			if _, ok := exprs[0].Type().(*FileType); !ok || !exprs[0].IsVariableExpr() {
+				return nil, fmt.Errorf("assign: first argument has to be a file variable, got %s instead", exprs[0].Type().TypeString())
+			}
+
+			if !exprCompatible(&StringType{}, exprs[1]) {
+				return nil, fmt.Errorf("assign: second argument has to be of string type, got %s instead", exprs[1].Type().TypeString())
+			}
+
+			return nil, nil
+		},
+	},
+	{
+		Name: "close",
+		validator: func(exprs []Expression) (DataType, error) {
+			if len(exprs) != 1 {
+				return nil, fmt.Errorf("close: need exactly 1 argument of file type")
+			}
+
+			if _, ok := exprs[0].Type().(*FileType); ok {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("close: need exactly 1 argument of file type, got %s instead", exprs[0].Type().TypeString())
+		},
+	},
+	{
+		Name: "seek",
+		validator: func(exprs []Expression) (DataType, error) {
+			if len(exprs) != 2 {
+				return nil, fmt.Errorf("seek: need exactly 2 arguments of typed file and integer type")
+			}
+
+			if err := validateTypedFile("seek", exprs[0]); err != nil {
+				return nil, err
+			}
+
+			if _, ok := exprs[1].Type().(*IntegerType); !ok {
+				return nil, fmt.Errorf("seek: second argument has to be of integer type, got %s instead", exprs[1].Type().TypeString())
+			}
+
+			return nil, nil
+		},
+	},
+	{
+		Name: "exit",
+		validator: func(exprs []Expression) (DataType, error) {
+			if len(exprs) != 0 {
+				return nil, fmt.Errorf("exit: no arguments allowed, got %d arguments instead", len(exprs))
+			}
+
+			return nil, nil
+		},
+	},
+	{
+		Name: "page",
+		validator: func(exprs []Expression) (DataType, error) {
+			switch len(exprs) {
+			case 0:
+				return nil, nil
+			case 1:
+				if IsTextType(exprs[0].Type()) && exprs[0].IsVariableExpr() {
+					return nil, nil
+				}
+				return nil, fmt.Errorf("page: argument has to be a text file variable, got %s instead", exprs[0].Type().TypeString())
+			}
+
+			return nil, fmt.Errorf("page: need at most 1 argument of text file type, got %d arguments instead", len(exprs))
+		},
+	},
 	{
 		Name: "unpack",
 		validator: func(exprs []Expression) (DataType, error) {
@@ -161,14 +237,28 @@ var builtinProcedures = []*Routine{
 	{
 		Name: "get",
 		validator: func(exprs []Expression) (DataType, error) {
-			// TODO: implement
+			if len(exprs) != 1 {
+				return nil, fmt.Errorf("get: need exactly 1 argument of file type")
+			}
+
+			if _, ok := exprs[0].Type().(*FileType); !ok || !exprs[0].IsVariableExpr() {
+				return nil, fmt.Errorf("get: argument has to be a file variable, got %s instead", exprs[0].Type().TypeString())
+			}
+
 			return nil, nil
 		},
 	},
 	{
 		Name: "put",
 		validator: func(exprs []Expression) (DataType, error) {
-			// TODO: implement
+			if len(exprs) != 1 {
+				return nil, fmt.Errorf("put: need exactly 1 argument of file type")
+			}
+
+			if _, ok := exprs[0].Type().(*FileType); !ok || !exprs[0].IsVariableExpr() {
+				return nil, fmt.Errorf("put: argument has to be a file variable, got %s instead", exprs[0].Type().TypeString())
+			}
+
 			return nil, nil
 		},
 	},
@@ -188,7 +278,10 @@ var builtinFunctions = []*Routine{
 			case *RealType:
 				return &RealType{}, nil
 			case *SubrangeType:
-				return exprs[0].Type(), nil
+				// the absolute value of a subrange value isn't necessarily within the subrange.
+				if isIntegerType(exprs[0].Type()) {
+					return &IntegerType{}, nil
+				}
 			}
 
 			return nil, fmt.Errorf("abs requires exactly 1 argument of type integer or real, got %s instead", exprs[0].Type().TypeString())
@@ -404,6 +497,48 @@ var builtinFunctions = []*Routine{
 			return nil, fmt.Errorf("pred requires exactly 1 argument of type enum or integer, got %s instead", exprs[0].Type().TypeString())
 		},
 	},
+	{
+		Name: "filepos",
+		validator: func(exprs []Expression) (DataType, error) {
+			if len(exprs) != 1 {
+				return nil, fmt.Errorf("filepos requires exactly 1 argument of typed file type, got %d arguments instead", len(exprs))
+			}
+
+			if err := validateTypedFile("filepos", exprs[0]); err != nil {
+				return nil, err
+			}
+
+			return &IntegerType{}, nil
+		},
+	},
+	{
+		Name: "filesize",
+		validator: func(exprs []Expression) (DataType, error) {
+			if len(exprs) != 1 {
+				return nil, fmt.Errorf("filesize requires exactly 1 argument of typed file type, got %d arguments instead", len(exprs))
+			}
+
+			if err := validateTypedFile("filesize", exprs[0]); err != nil {
+				return nil, err
+			}
+
+			return &IntegerType{}, nil
+		},
+	},
+	{
+		Name: "length",
+		validator: func(exprs []Expression) (DataType, error) {
+			if len(exprs) != 1 {
+				return nil, fmt.Errorf("length requires exactly 1 argument of type string, got %d arguments instead", len(exprs))
+			}
+
+			if _, ok := exprs[0].Type().(*StringType); !ok {
+				return nil, fmt.Errorf("length requires exactly 1 argument of type string, got %s instead", exprs[0].Type().TypeString())
+			}
+
+			return &IntegerType{}, nil
+		},
+	},
 	{
 		Name: "eof",
 		validator: func(exprs []Expression) (DataType, error) {
@@ -423,19 +558,35 @@ var builtinFunctions = []*Routine{
 		Name: "eoln",
 		validator: func(exprs []Expression) (DataType, error) {
 			if len(exprs) != 1 {
-				return nil, fmt.Errorf("eoln requires exactly 1 argument of file type, got %d arguments instead", len(exprs))
+				return nil, fmt.Errorf("eoln requires exactly 1 argument of text file type, got %d arguments instead", len(exprs))
 			}
 
-			switch exprs[0].Type().(type) {
-			case *FileType:
+			if IsTextType(exprs[0].Type()) {
 				return booleanTypeDef.Type, nil
 			}
 
-			return nil, fmt.Errorf("eoln requires exactly 1 argument of file type, got %s instead", exprs[0].Type().TypeString())
+			return nil, fmt.Errorf("eoln requires exactly 1 argument of text file type, got %s instead", exprs[0].Type().TypeString())
 		},
 	},
 }
 
+// nonISOBuiltins contains the names of builtin procedures and functions that are
+// not part of ISO Pascal, but are known from Turbo Pascal and Delphi.
+var nonISOBuiltins = map[string]bool{
+	"inc":      true,
+	"dec":      true,
+	"assign":   true,
+	"close":    true,
+	"seek":     true,
+	"exit":     true,
+	"frac":     true,
+	"int":      true,
+	"pi":       true,
+	"filepos":  true,
+	"filesize": true,
+	"length":   true,
+}
+
 func getBuiltinType(identifier string) DataType {
 	switch identifier {
 	case "integer":
@@ -463,6 +614,18 @@ func validateReadParameters(exprs []Expression) (DataType, error) {
 	return nil, nil
 }
 
+func validateTypedFile(name string, expr Expression) error {
+	if _, ok := expr.Type().(*FileType); !ok {
+		return fmt.Errorf("%s: argument has to be of typed file type, got %s instead", name, expr.Type().TypeString())
+	}
+
+	if IsTextType(expr.Type()) {
+		return fmt.Errorf("%s: argument has to be of typed file type, got text file instead", name)
+	}
+
+	return nil
+}
+
 var booleanTypeDef = &TypeDefinition{
 	Name: "boolean",
 	Type: &EnumType{
@@ -479,11 +642,32 @@ var textTypeDef = &TypeDefinition{
 	},
 }
 
+var inputVariable = &Variable{
+	Name: "input",
+	Type: textTypeDef.Type,
+}
+
+var outputVariable = &Variable{
+	Name: "output",
+	Type: textTypeDef.Type,
+}
+
+// IsStandardFile returns true if the provided variable is one of the standard
+// text files input and output, false otherwise.
+func IsStandardFile(v *Variable) bool {
+	return v == inputVariable || v == outputVariable
+}
+
 // IsBooleanType returns true if the provided type is the boolean type, false otherwise.
 func IsBooleanType(dt DataType) bool {
 	return booleanTypeDef.Type.Equals(dt)
 }
 
+// IsTextType returns true if the provided type is the text type, false otherwise.
+func IsTextType(dt DataType) bool {
+	return textTypeDef.Type.Equals(dt)
+}
+
 // IsCharType returns true if the provided type is the char type, false otherwise.
 func IsCharType(dt DataType) bool {
 	result := dt.Equals(&CharType{})
@@ -501,4 +685,8 @@ var builtinBlock = &Block{
 		booleanTypeDef,
 		textTypeDef,
 	},
+	Variables: []*Variable{
+		inputVariable,
+		outputVariable,
+	},
 }
@@ -94,7 +94,7 @@ func (e *RelationalExpr) Reduce() Expression {
 }
 
 func isAdditionOperator(typ itemType) bool {
-	return typ == itemSign || typ == itemOr
+	return typ == itemSign || typ == itemOr || typ == itemSymmetricDifference
 }
 
 // SimpleExpr describes a simple expression, which consists of an optional
@@ -176,6 +176,10 @@ const (
 	OperatorAdd      AdditionOperator = "+"
 	OperatorSubtract AdditionOperator = "-"
 	OperatorOr       AdditionOperator = "or"
+
+	// OperatorSymmetricDifference is the symmetric difference of two sets, as known
+	// from some Pascal dialects.
+	OperatorSymmetricDifference AdditionOperator = "><"
 )
 
 func tokenToAdditionOperator(t item) AdditionOperator {
@@ -185,6 +189,9 @@ func tokenToAdditionOperator(t item) AdditionOperator {
 	if t.typ == itemOr {
 		return OperatorOr
 	}
+	if t.typ == itemSymmetricDifference {
+		return OperatorSymmetricDifference
+	}
 	return AdditionOperator(fmt.Sprintf("INVALID(%+v)", t))
 }
 
@@ -232,13 +239,13 @@ func (e *TermExpr) Reduce() Expression {
 		First: e.First.Reduce(),
 	}
 
-	for _, mul := range ne.Next {
+	for _, mul := range e.Next {
 		ne.Next = append(ne.Next, &Multiplication{
 			Operator: mul.Operator,
 			Factor:   mul.Factor.Reduce(),
 		})
 	}
-	return e
+	return ne
 }
 
 // Multipliciation describes a multiplication operator and a factor used in a term.
@@ -530,8 +537,15 @@ func (e *SubExpr) IsVariableExpr() bool {
 	return e.Expr.IsVariableExpr() // TODO: check whether this is correct.
 }
 
+// Reduce only removes the parentheses around expressions that aren't made up
+// of operators, as otherwise the grouping of the operands would be lost.
 func (e *SubExpr) Reduce() Expression {
-	return e.Expr.Reduce()
+	expr := e.Expr.Reduce()
+	switch expr.(type) {
+	case *RelationalExpr, *SimpleExpr, *TermExpr:
+		return &SubExpr{Expr: expr}
+	}
+	return expr
 }
 
 // IndexedVariableExpr describes an indexed access of an element of an expression (which is of an array type).
@@ -594,6 +608,10 @@ type FunctionCallExpr struct {
 	Type_        DataType
 	ActualParams []Expression
 	FormalParams []*FormalParameter
+
+	// The function that is called, which tells builtin functions apart from
+	// user-declared functions of the same name.
+	Routine *Routine
 }
 
 func (e *FunctionCallExpr) String() string {
@@ -620,8 +638,10 @@ func (e *FunctionCallExpr) IsVariableExpr() bool {
 
 func (e *FunctionCallExpr) Reduce() Expression {
 	ne := &FunctionCallExpr{
-		Name:  e.Name,
-		Type_: e.Type_,
+		Name:         e.Name,
+		Type_:        e.Type_,
+		FormalParams: e.FormalParams,
+		Routine:      e.Routine,
 	}
 
 	for _, pe := range e.ActualParams {
@@ -660,6 +680,31 @@ func (e *FieldDesignatorExpr) Reduce() Expression {
 	}
 }
 
+// TypeCastExpr describes the conversion of an ordinal value Expr to the ordinal type Type_.
+type TypeCastExpr struct {
+	Expr  Expression
+	Type_ DataType
+}
+
+func (e *TypeCastExpr) String() string {
+	return fmt.Sprintf("type-cast-expr:<%s to %s>", e.Expr, e.Type_.TypeString())
+}
+
+func (e *TypeCastExpr) Type() DataType {
+	return e.Type_
+}
+
+func (e *TypeCastExpr) IsVariableExpr() bool {
+	return false
+}
+
+func (e *TypeCastExpr) Reduce() Expression {
+	return &TypeCastExpr{
+		Expr:  e.Expr.Reduce(),
+		Type_: e.Type_,
+	}
+}
+
 // EnumValueExpr describes an enum value, with the enum value's name, its integer value,
 // and the enum data type it is of.
 type EnumValueExpr struct {
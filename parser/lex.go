@@ -15,10 +15,13 @@ type item struct {
 func (i item) String() string {
 	switch {
 	case i.typ == itemEOF:
-		return "EOF"
+		return itemTypeName[itemEOF]
 	case i.typ == itemError:
 		return i.val
 	}
+	if name, ok := itemTypeName[i.typ]; ok {
+		return fmt.Sprintf("%s %q", name, i.val)
+	}
 	return fmt.Sprintf("%q", i.val)
 }
 
@@ -89,8 +92,20 @@ const (
 	itemMultiply
 	itemFloatDivide
 	itemForward
+	itemSymmetricDifference
+	itemControlCharacter
 )
 
+// itemTypeName contains human-readable names of item types whose values alone
+// don't make clear what kind of token was encountered.
+var itemTypeName = map[itemType]string{
+	itemEOF:                   "end-of-file",
+	itemIdentifier:            "identifier",
+	itemUnsignedDigitSequence: "number",
+	itemStringLiteral:         "string literal",
+	itemControlCharacter:      "control character",
+}
+
 var key = map[string]itemType{
 	"and":       itemAnd,
 	"array":     itemArray,
@@ -143,6 +158,30 @@ type lexer struct {
 	width   pos
 	lastPos pos
 	items   chan item
+
+	lineComments      bool // if true, // starts a comment that extends to the end of the line.
+	controlCharacters bool // if true, ^ followed by a single letter is a control character.
+}
+
+// lexOption configures the lexer before it starts lexing.
+type lexOption func(*lexer)
+
+// withControlCharacters makes the lexer treat ^ that is immediately followed by a
+// single letter as a control character, e.g. ^M for carriage return, as in Turbo Pascal.
+// As this is ambiguous with pointer types to types with single-letter names, it is
+// up to the parser to decide how to interpret it.
+func withControlCharacters() lexOption {
+	return func(l *lexer) {
+		l.controlCharacters = true
+	}
+}
+
+// withLineComments makes the lexer treat // as the start of a comment
+// that extends to the end of the line, as in Delphi.
+func withLineComments() lexOption {
+	return func(l *lexer) {
+		l.lineComments = true
+	}
 }
 
 func (l *lexer) next() rune {
@@ -203,6 +242,29 @@ func (l *lexer) columnInLine() int {
 	return int(l.lastPos) - bolPos + 1
 }
 
+// lineSnippet returns the source line of the last item, followed by a line that
+// points at the item's start with a caret.
+func (l *lexer) lineSnippet() string {
+	bolPos := strings.LastIndex(l.input[:l.lastPos], "\n") + 1
+	eolPos := strings.IndexByte(l.input[bolPos:], '\n')
+	if eolPos < 0 {
+		eolPos = len(l.input)
+	} else {
+		eolPos += bolPos
+	}
+	line := strings.TrimSuffix(l.input[bolPos:eolPos], "\r")
+
+	// keep tabs in the indentation of the caret so that it lines up with the source line.
+	indent := strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, l.input[bolPos:l.lastPos])
+
+	return line + "\n" + indent + "^"
+}
+
 func (l *lexer) errorf(format string, args ...interface{}) stateFn {
 	l.items <- item{itemError, l.start, fmt.Sprintf(format, args...)}
 	return nil
@@ -214,12 +276,15 @@ func (l *lexer) nextItem() item {
 	return item
 }
 
-func lex(name, input string) *lexer {
+func lex(name, input string, opts ...lexOption) *lexer {
 	l := &lexer{
 		name:  name,
 		input: input,
 		items: make(chan item),
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
 	go l.run()
 	return l
 }
@@ -292,6 +357,9 @@ func lexText(l *lexer) stateFn {
 		return lexText
 	case r == '/':
 		l.next()
+		if l.lineComments && l.peek() == '/' {
+			return lexLineComment
+		}
 		l.emit(itemFloatDivide)
 		return lexText
 	case r == '.':
@@ -310,6 +378,10 @@ func lexText(l *lexer) stateFn {
 		return lexText
 	case r == '^':
 		l.next()
+		if l.controlCharacters && l.acceptControlCharacterLetter() {
+			l.emit(itemControlCharacter)
+			return lexText
+		}
 		l.emit(itemCaret)
 		return lexText
 	case r == '@':
@@ -359,10 +431,14 @@ func lexRelationalOperator(l *lexer) stateFn {
 		}
 	case '>':
 		r = l.peek()
-		if r == '=' {
+		switch r {
+		case '=':
 			l.next()
 			l.emit(itemGreaterEqual)
-		} else {
+		case '<':
+			l.next()
+			l.emit(itemSymmetricDifference)
+		default:
 			l.emit(itemGreater)
 		}
 	default:
@@ -402,6 +478,13 @@ func lexStringLiteral(l *lexer) stateFn {
 	return lexText
 }
 
+func lexLineComment(l *lexer) stateFn {
+	for r := l.next(); r != eof && r != '\n'; r = l.next() {
+	}
+	l.ignore()
+	return lexText
+}
+
 func lexComment(l *lexer) stateFn {
 	r := l.next()
 	if r == '(' {
@@ -415,3 +498,16 @@ func lexComment(l *lexer) stateFn {
 	l.ignore()
 	return lexText
 }
+
+// acceptControlCharacterLetter consumes a single letter if it is not followed by
+// further characters of an identifier, i.e. if ^ and the letter form a control character.
+func (l *lexer) acceptControlCharacterLetter() bool {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	start := l.pos
+	if !l.accept(letters) || strings.ContainsRune("0123456789"+letters, l.peek()) {
+		l.pos = start
+		return false
+	}
+	return true
+}
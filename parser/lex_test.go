@@ -1,6 +1,9 @@
 package parser
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestLexer(t *testing.T) {
 	testData := []string{
@@ -90,3 +93,100 @@ testLoop:
 		}
 	}
 }
+
+func TestLexerLineComments(t *testing.T) {
+	input := "a := b // c / d\n+ e"
+
+	lexItems := func(opts ...lexOption) (types []itemType) {
+		l := lex("", input, opts...)
+		for item := l.nextItem(); item.typ != itemEOF && item.typ != itemError; item = l.nextItem() {
+			types = append(types, item.typ)
+		}
+		return types
+	}
+
+	expectedWithout := []itemType{
+		itemIdentifier, itemAssignment, itemIdentifier, itemFloatDivide, itemFloatDivide,
+		itemIdentifier, itemFloatDivide, itemIdentifier, itemSign, itemIdentifier,
+	}
+	if got := lexItems(); !reflect.DeepEqual(got, expectedWithout) {
+		t.Errorf("without line comments: got %v, expected %v", got, expectedWithout)
+	}
+
+	expectedWith := []itemType{
+		itemIdentifier, itemAssignment, itemIdentifier, itemSign, itemIdentifier,
+	}
+	if got := lexItems(withLineComments()); !reflect.DeepEqual(got, expectedWith) {
+		t.Errorf("with line comments: got %v, expected %v", got, expectedWith)
+	}
+}
+
+func TestLexerSymmetricDifference(t *testing.T) {
+	var types []itemType
+	l := lex("", "a >< b > c")
+	for item := l.nextItem(); item.typ != itemEOF && item.typ != itemError; item = l.nextItem() {
+		types = append(types, item.typ)
+	}
+
+	expected := []itemType{itemIdentifier, itemSymmetricDifference, itemIdentifier, itemGreater, itemIdentifier}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("got %v, expected %v", types, expected)
+	}
+}
+
+func TestLexerControlCharacters(t *testing.T) {
+	input := "ch := ^M; p := q^; r := ^ab"
+
+	lexItems := func(opts ...lexOption) (items []item) {
+		l := lex("", input, opts...)
+		for item := l.nextItem(); item.typ != itemEOF && item.typ != itemError; item = l.nextItem() {
+			items = append(items, item)
+		}
+		return items
+	}
+
+	types := func(items []item) (types []itemType) {
+		for _, item := range items {
+			types = append(types, item.typ)
+		}
+		return types
+	}
+
+	expectedWithout := []itemType{
+		itemIdentifier, itemAssignment, itemCaret, itemIdentifier, itemSemicolon,
+		itemIdentifier, itemAssignment, itemIdentifier, itemCaret, itemSemicolon,
+		itemIdentifier, itemAssignment, itemCaret, itemIdentifier,
+	}
+	if got := types(lexItems()); !reflect.DeepEqual(got, expectedWithout) {
+		t.Errorf("without control characters: got %v, expected %v", got, expectedWithout)
+	}
+
+	items := lexItems(withControlCharacters())
+	expectedWith := []itemType{
+		itemIdentifier, itemAssignment, itemControlCharacter, itemSemicolon,
+		itemIdentifier, itemAssignment, itemIdentifier, itemCaret, itemSemicolon,
+		itemIdentifier, itemAssignment, itemCaret, itemIdentifier,
+	}
+	if got := types(items); !reflect.DeepEqual(got, expectedWith) {
+		t.Errorf("with control characters: got %v, expected %v", got, expectedWith)
+	}
+	if items[2].val != "^M" {
+		t.Errorf("got control character %q, expected %q", items[2].val, "^M")
+	}
+}
+
+func TestLexerBracketDigraphs(t *testing.T) {
+	expected := []itemType{itemIdentifier, itemOpenBracket, itemIdentifier, itemCloseBracket}
+
+	for _, input := range []string{"a(.i.)", "a[i]", "a(.i]", "a[i.)"} {
+		var types []itemType
+		l := lex("", input)
+		for item := l.nextItem(); item.typ != itemEOF && item.typ != itemError; item = l.nextItem() {
+			types = append(types, item.typ)
+		}
+
+		if !reflect.DeepEqual(types, expected) {
+			t.Errorf("%q: got %v, expected %v", input, types, expected)
+		}
+	}
+}
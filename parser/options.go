@@ -0,0 +1,114 @@
+package parser
+
+// Option configures the parser, e.g. to enable language extensions that are
+// not part of ISO Pascal.
+type Option func(*parser)
+
+// WithInlinePointerTypes enables pointer types to inline type definitions,
+// e.g. ^record a : integer end. ISO Pascal only allows pointers to type
+// identifiers. The inline type is added as a type definition with a synthesized
+// name to the block where it was declared.
+func WithInlinePointerTypes() Option {
+	return func(p *parser) {
+		p.inlinePointerTypes = true
+	}
+}
+
+// WithExplicitEnumValues enables explicit values for identifiers of enumerated types as
+// they are known from Delphi, e.g. (a = 1, b = 2, c = 5). Identifiers without explicit
+// value have the value of their predecessor plus one. The values must be ascending.
+func WithExplicitEnumValues() Option {
+	return func(p *parser) {
+		p.explicitEnumValues = true
+	}
+}
+
+// WithLineComments enables line comments as they are known from Delphi: //
+// starts a comment that extends to the end of the line. ISO Pascal has no line
+// comments, and interprets // as two consecutive / operators.
+func WithLineComments() Option {
+	return func(p *parser) {
+		p.lexerOptions = append(p.lexerOptions, withLineComments())
+	}
+}
+
+// WithErrorSnippets makes error messages include the source line where the error
+// occurred, followed by a line with a caret that points at the offending token.
+func WithErrorSnippets() Option {
+	return func(p *parser) {
+		p.errorSnippets = true
+	}
+}
+
+// WithTypedConstants enables typed constants as they are known from Turbo Pascal,
+// e.g. const m : array[1..2, 1..2] of integer = ((1, 2), (3, 4)); Typed constants
+// are added to the block as variables with an initial value.
+func WithTypedConstants() Option {
+	return func(p *parser) {
+		p.typedConstants = true
+	}
+}
+
+// WithFunctionCallStatements enables calling functions as statements, as it is known
+// from Turbo Pascal and Delphi. The function's result is discarded. ISO Pascal only
+// allows procedures to be called as statements.
+func WithFunctionCallStatements() Option {
+	return func(p *parser) {
+		p.functionCallStatements = true
+	}
+}
+
+// WithControlCharacters enables control characters as they are known from Turbo
+// Pascal: ^ immediately followed by a single letter denotes the control character
+// of that letter, e.g. ^M for carriage return. In type definitions, ^ followed by a
+// single letter still denotes a pointer type.
+func WithControlCharacters() Option {
+	return func(p *parser) {
+		p.lexerOptions = append(p.lexerOptions, withControlCharacters())
+	}
+}
+
+// WithStraySemicolons makes the parser skip extra semicolons between the last
+// declaration and the begin of a statement part, as they are occasionally found
+// in programs written for more lenient compilers.
+func WithStraySemicolons() Option {
+	return func(p *parser) {
+		p.straySemicolons = true
+	}
+}
+
+// WithWritableSets allows sets to be written with write and writeln, which is
+// useful for debugging. Their elements are written in ascending order, e.g. [1,2,3].
+// ISO Pascal doesn't allow writing sets.
+func WithWritableSets() Option {
+	return func(p *parser) {
+		p.writableSets = true
+	}
+}
+
+// WithTypeCasts allows ordinal type names to be used like functions to convert ordinal
+// values, as it is known from Delphi, e.g. integer(ch) or char(n). ISO Pascal only
+// provides ord and chr for such conversions.
+func WithTypeCasts() Option {
+	return func(p *parser) {
+		p.typeCasts = true
+	}
+}
+
+// WithStrictISO makes the parser reject constructs that it accepts by default, even
+// though they are not part of ISO Pascal, such as the string type and builtin routines
+// like inc, dec or exit. Extensions that are enabled by other options remain available.
+func WithStrictISO() Option {
+	return func(p *parser) {
+		p.strictISO = true
+	}
+}
+
+// WithMaxNestingDepth sets the maximum depth to which expressions and statements may be
+// nested, e.g. by parentheses or compound statements. Programs that exceed it are rejected,
+// rather than the parser running out of stack space. The default is 1000.
+func WithMaxNestingDepth(depth int) Option {
+	return func(p *parser) {
+		p.maxNestingDepth = depth
+	}
+}
@@ -6,16 +6,23 @@ import (
 	"io"
 	"log"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 )
 
-func newParser(name, text string) *parser {
-	return &parser{
-		lexer:      lex(name, text),
-		logger:     log.New(io.Discard, "parser", log.LstdFlags|log.Lshortfile),
-		enumValues: make(map[string]*EnumValue),
+func newParser(name, text string, opts ...Option) *parser {
+	p := &parser{
+		logger:          log.New(io.Discard, "parser", log.LstdFlags|log.Lshortfile),
+		enumValues:      make(map[string]*EnumValue),
+		usedBuiltins:    make(map[string]bool),
+		maxNestingDepth: defaultMaxNestingDepth,
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	p.lexer = lex(name, text, p.lexerOptions...)
+	return p
 }
 
 func (p *parser) setLogOutput(w io.Writer) {
@@ -31,17 +38,9 @@ func (p *parser) Parse() (ast *AST, err error) {
 // Parse parses a single Pascal file, identified by name. The
 // file content must be provided in text. It returns the
 // Abstract Syntax Tree (AST) as a *AST object, or an error.
-func Parse(name, text string) (ast *AST, err error) {
-	ast, err = parseWithLexer(lex(name, text))
-	return ast, err
-}
-
-func parseWithLexer(lexer *lexer) (ast *AST, err error) {
-	p := &parser{
-		lexer:      lexer,
-		logger:     log.New(io.Discard, "parser", log.LstdFlags|log.Lshortfile),
-		enumValues: make(map[string]*EnumValue),
-	}
+// The parser's behaviour can be configured using options.
+func Parse(name, text string, opts ...Option) (ast *AST, err error) {
+	p := newParser(name, text, opts...)
 	defer p.recover(&err)
 	ast, err = p.parse()
 	if err == nil {
@@ -58,8 +57,32 @@ type parser struct {
 
 	enumValues    map[string]*EnumValue
 	enumValueList []string
+
+	usedBuiltins map[string]bool // names of the builtin procedures and functions that are called.
+
+	lexerOptions []lexOption // options that are passed on to the lexer.
+
+	inlinePointerTypes     bool // if true, pointers to inline type definitions are allowed.
+	explicitEnumValues     bool // if true, identifiers of enumerated types may have explicit values.
+	anonTypeCount          int  // number of type definitions with synthesized names.
+	errorSnippets          bool // if true, error messages include the offending source line.
+	typedConstants         bool // if true, typed constants are allowed in constant definition parts.
+	functionCallStatements bool // if true, functions can be called as statements, discarding their result.
+	straySemicolons        bool // if true, extra semicolons before begin are skipped.
+	writableSets           bool // if true, sets can be written with write and writeln.
+	typeCasts              bool // if true, ordinal type names can be used to convert values.
+	strictISO              bool // if true, constructs that are not part of ISO Pascal are rejected.
+
+	programFiles []string // files provided in the program heading.
+
+	maxNestingDepth int // maximum nesting depth of expressions and statements.
+	nestingDepth    int // current nesting depth of expressions and statements.
 }
 
+// defaultMaxNestingDepth is the maximum nesting depth of expressions and statements
+// unless configured otherwise. It protects against running out of stack space.
+const defaultMaxNestingDepth = 1000
+
 // AST describes the Abstract Syntax Tree of the parsed Pascal program.
 type AST struct {
 	// Program name
@@ -72,6 +95,10 @@ type AST struct {
 	// declarations and definitions as well as the main program
 	// to be executed.
 	Block *Block
+
+	// UsedBuiltins contains the sorted names of all builtin procedures and functions
+	// that the program calls.
+	UsedBuiltins []string
 }
 
 type EnumValue struct {
@@ -91,6 +118,19 @@ func (p *parser) recover(errp *error) {
 	}
 }
 
+// enterNesting increases the nesting depth, and reports an error that the kind of
+// construct is too deeply nested if the maximum nesting depth is exceeded. It returns
+// a function that restores the previous nesting depth.
+func (p *parser) enterNesting(kind string) func() {
+	p.nestingDepth++
+	if p.nestingDepth > p.maxNestingDepth {
+		p.errorf("%s too deeply nested", kind)
+	}
+	return func() {
+		p.nestingDepth--
+	}
+}
+
 func (p *parser) backup() {
 	p.peekCount++
 }
@@ -114,8 +154,37 @@ func (p *parser) next() item {
 	return i
 }
 
+// ParseError describes an error that occurred at a particular position in the
+// parsed source code.
+type ParseError struct {
+	File   string // name of the parsed file.
+	Line   int    // line where the error occurred.
+	Column int    // column where the error occurred.
+	Msg    string // the error message without position information.
+
+	// If not empty, the source line where the error occurred, followed by a line
+	// that points at the error's position.
+	Snippet string
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+	if e.Snippet != "" {
+		msg += "\n" + e.Snippet
+	}
+	return msg
+}
+
 func (p *parser) errorf(fmtstr string, args ...interface{}) {
-	err := errors.New(fmt.Sprintf("%s:%d:%d: ", p.lexer.name, p.lexer.lineNumber(), p.lexer.columnInLine()) + fmt.Sprintf(fmtstr, args...))
+	err := &ParseError{
+		File:   p.lexer.name,
+		Line:   p.lexer.lineNumber(),
+		Column: p.lexer.columnInLine(),
+		Msg:    fmt.Sprintf(fmtstr, args...),
+	}
+	if p.errorSnippets {
+		err.Snippet = p.lexer.lineSnippet()
+	}
 	panic(err)
 }
 
@@ -137,9 +206,29 @@ func (p *parser) parse() (ast *AST, err error) {
 	}
 	p.next()
 
+	if p.peek().typ != itemEOF {
+		p.errorf("unexpected %s after end of program", p.peek())
+	}
+
+	for name := range p.usedBuiltins {
+		ast.UsedBuiltins = append(ast.UsedBuiltins, name)
+	}
+	sort.Strings(ast.UsedBuiltins)
+
 	return ast, nil
 }
 
+// recordBuiltinCall records that the routine is called if it is a builtin procedure or function.
+// In strict ISO mode, builtins that are not part of ISO Pascal are rejected.
+func (p *parser) recordBuiltinCall(routine *Routine) {
+	if routine.IsBuiltin() {
+		if p.strictISO && nonISOBuiltins[routine.Name] {
+			p.errorf("%s is not part of ISO Pascal", routine.Name)
+		}
+		p.usedBuiltins[routine.Name] = true
+	}
+}
+
 // parseProgramHeading parses a program heading.
 //
 //	program-heading =
@@ -159,6 +248,7 @@ func (p *parser) parseProgramHeading(ast *AST) {
 		p.next()
 
 		ast.Files = p.parseIdentifierList(nil)
+		p.programFiles = ast.Files
 
 		if p.peek().typ != itemCloseParen {
 			p.errorf("expected ), got %s instead", p.peek())
@@ -216,6 +306,12 @@ func (p *parser) parseDeclarationPart(b *Block) {
 //	statement-part =
 //	    "begin" [ statement-sequence ] "end"
 func (p *parser) parseStatementPart(b *Block) {
+	if p.straySemicolons {
+		for p.peek().typ == itemSemicolon {
+			p.next()
+		}
+	}
+
 	if p.peek().typ != itemBegin {
 		p.errorf("expected begin, got %s instead", p.next())
 	}
@@ -277,10 +373,7 @@ func (p *parser) parseConstantDefinitionPart(b *Block) {
 		p.errorf("expected constant identifier, got %s instead", p.peek())
 	}
 
-	constDecl := p.parseConstantDefinition(b)
-	if err := b.addConstantDefinition(constDecl); err != nil {
-		p.errorf("%v", err)
-	}
+	p.parseConstantOrTypedConstantDefinition(b)
 
 	if p.peek().typ != itemSemicolon {
 		p.errorf("expected semicolon, got %s", p.next())
@@ -288,10 +381,7 @@ func (p *parser) parseConstantDefinitionPart(b *Block) {
 	p.next()
 
 	for p.peek().typ == itemIdentifier {
-		constDecl := p.parseConstantDefinition(b)
-		if err := b.addConstantDefinition(constDecl); err != nil {
-			p.errorf("%v", err)
-		}
+		p.parseConstantOrTypedConstantDefinition(b)
 
 		if p.peek().typ != itemSemicolon {
 			p.errorf("expected semicolon, got %s", p.next())
@@ -300,30 +390,223 @@ func (p *parser) parseConstantDefinitionPart(b *Block) {
 	}
 }
 
-type ConstantDefinition struct {
-	Name  string
-	Value ConstantLiteral
-}
-
-// parseConstantDefinition parses a constant definition.
+// parseConstantOrTypedConstantDefinition parses either a constant definition or,
+// if typed constants are enabled, a typed constant definition, and adds it to the block.
+// Typed constants are added to the block as initialized variables.
 //
 //	constant-definition =
-//	    identifier "=" constant .
-func (p *parser) parseConstantDefinition(b *Block) *ConstantDefinition {
+//	    identifier "=" constant-expression .
+//	typed-constant-definition =
+//	    identifier ":" type-denoter "=" typed-constant .
+func (p *parser) parseConstantOrTypedConstantDefinition(b *Block) {
 	if p.peek().typ != itemIdentifier {
 		p.errorf("expected constant identifier, got %s instead", p.peek())
 	}
 
 	constName := p.next().val
 
+	if p.typedConstants && p.peek().typ == itemColon {
+		p.next()
+
+		typ := p.parseType(b, "")
+
+		if p.peek().typ != itemEqual {
+			p.errorf("expected =, got %s", p.next())
+		}
+		p.next()
+
+		value := p.parseTypedConstant(b, typ)
+
+		if err := b.addVariable(&Variable{Name: constName, Type: typ, InitialValue: value}); err != nil {
+			p.errorf("%v", err)
+		}
+		return
+	}
+
 	if p.peek().typ != itemEqual {
 		p.errorf("expected =, got %s", p.next())
 	}
 	p.next()
 
-	constValue := p.parseConstant(b)
+	constValue := p.parseConstantExpression(b)
+
+	if err := b.addConstantDefinition(&ConstantDefinition{Name: constName, Value: constValue}); err != nil {
+		p.errorf("%v", err)
+	}
+}
+
+// parseTypedConstant parses the value of a typed constant of type typ. Values of
+// array types are written as parenthesized, comma-separated lists of values, one
+// for each element of the array. Values of multi-dimensional arrays are nested
+// accordingly.
+//
+//	typed-constant =
+//	    constant-expression | array-constant .
+//	array-constant =
+//	    "(" typed-constant { "," typed-constant } ")" .
+func (p *parser) parseTypedConstant(b *Block, typ DataType) ConstantLiteral {
+	arrType, ok := typ.(*ArrayType)
+	if !ok {
+		value := p.parseConstantExpression(b)
+		if !labelCompatibleWithType(value, typ) && !typesCompatibleForAssignment(typ, value.ConstantType()) {
+			p.errorf("constant of type %s is not compatible with type %s", value.ConstantType().TypeString(), typ.TypeString())
+		}
+		return value
+	}
+
+	length, ok := indexTypeLength(arrType.IndexTypes[0])
+	if !ok {
+		p.errorf("unsupported index type %s in typed constant", arrType.IndexTypes[0].TypeString())
+	}
+
+	elementType := arrType.ElementType
+	if len(arrType.IndexTypes) > 1 {
+		elementType = &ArrayType{IndexTypes: arrType.IndexTypes[1:], ElementType: arrType.ElementType, Packed: arrType.Packed}
+	}
+
+	if p.peek().typ != itemOpenParen {
+		p.errorf("expected (, got %s", p.next())
+	}
+	p.next()
+
+	lit := &ArrayLiteral{Type_: typ}
 
-	return &ConstantDefinition{Name: constName, Value: constValue}
+	for {
+		lit.Elements = append(lit.Elements, p.parseTypedConstant(b, elementType))
+
+		if p.peek().typ != itemComma {
+			break
+		}
+		p.next()
+	}
+
+	if p.peek().typ != itemCloseParen {
+		p.errorf("expected ), got %s", p.next())
+	}
+	p.next()
+
+	if len(lit.Elements) != length {
+		p.errorf("expected %d values for %s, got %d", length, typ.TypeString(), len(lit.Elements))
+	}
+
+	return lit
+}
+
+type ConstantDefinition struct {
+	Name  string
+	Value ConstantLiteral
+}
+
+// parseConstantExpression parses a constant expression. Unlike ISO Pascal, which only
+// allows a single constant, constants can be combined using integer arithmetic. The
+// result is folded into a single constant. Like in a simple expression, a leading sign
+// applies to the whole first term, i.e. -7 mod 3 is -(7 mod 3).
+//
+//	constant-expression =
+//	    [ sign ] constant-term { ( "+" | "-" ) constant-term } .
+func (p *parser) parseConstantExpression(b *Block) ConstantLiteral {
+	minus := false
+	if p.peek().typ == itemSign {
+		minus = p.next().val == "-"
+	}
+
+	value := p.parseConstantTerm(b)
+
+	if minus {
+		negated, err := value.Negate()
+		if err != nil {
+			p.errorf("%v", err)
+		}
+		value = negated
+	}
+
+	for p.peek().typ == itemSign {
+		p.verifyConstantExpressionAllowed()
+
+		operator := p.next().val
+
+		nextValue := p.parseConstantTerm(b)
+
+		folded, err := foldIntegerConstants(operator, value, nextValue)
+		if err != nil {
+			p.errorf("%v", err)
+		}
+		value = folded
+	}
+
+	return value
+}
+
+// parseConstantTerm parses a constant term.
+//
+//	constant-term =
+//	    constant { ( "*" | "div" | "mod" ) constant } .
+func (p *parser) parseConstantTerm(b *Block) ConstantLiteral {
+	value := p.parseConstantWithoutSign(b, false)
+
+	for typ := p.peek().typ; typ == itemMultiply || typ == itemDiv || typ == itemMod; typ = p.peek().typ {
+		p.verifyConstantExpressionAllowed()
+
+		operator := p.next().val
+
+		nextValue := p.parseConstant(b)
+
+		folded, err := foldIntegerConstants(strings.ToLower(operator), value, nextValue)
+		if err != nil {
+			p.errorf("%v", err)
+		}
+		value = folded
+	}
+
+	return value
+}
+
+// verifyConstantExpressionAllowed reports an error in strict ISO mode, as ISO Pascal
+// only allows a single constant where this parser accepts constant expressions.
+func (p *parser) verifyConstantExpressionAllowed() {
+	if p.strictISO {
+		p.errorf("constant expressions are not part of ISO Pascal")
+	}
+}
+
+// foldIntegerConstants applies the operator to two integer constants and returns the result
+// as a new integer constant. The semantics of mod follow ISO Pascal, i.e. the result is never
+// negative.
+func foldIntegerConstants(operator string, left, right ConstantLiteral) (ConstantLiteral, error) {
+	l, ok := left.(*IntegerLiteral)
+	if !ok {
+		return nil, fmt.Errorf("can't use %s operator in constant with %s", operator, left.ConstantType().TypeString())
+	}
+
+	r, ok := right.(*IntegerLiteral)
+	if !ok {
+		return nil, fmt.Errorf("can't use %s operator in constant with %s", operator, right.ConstantType().TypeString())
+	}
+
+	switch operator {
+	case "+":
+		return &IntegerLiteral{Value: l.Value + r.Value}, nil
+	case "-":
+		return &IntegerLiteral{Value: l.Value - r.Value}, nil
+	case "*":
+		return &IntegerLiteral{Value: l.Value * r.Value}, nil
+	case "div":
+		if r.Value == 0 {
+			return nil, errors.New("division by zero in constant")
+		}
+		return &IntegerLiteral{Value: l.Value / r.Value}, nil
+	case "mod":
+		if r.Value <= 0 {
+			return nil, fmt.Errorf("mod requires a positive right operand, got %d instead", r.Value)
+		}
+		value := l.Value % r.Value
+		if value < 0 {
+			value += r.Value
+		}
+		return &IntegerLiteral{Value: value}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator %s in constant", operator)
 }
 
 // parseTypeDefinitionPart parses a type definition part.
@@ -454,7 +737,13 @@ restartParseDataType:
 	case itemIdentifier:
 		ident := p.peek().val
 		if typ := getBuiltinType(ident); typ != nil {
+			if _, ok := typ.(*StringType); ok && p.strictISO {
+				p.errorf("type string is not part of ISO Pascal")
+			}
 			p.next()
+			if st, ok := typ.(*StringType); ok && p.peek().typ == itemOpenBracket {
+				st.MaxLength = p.parseStringLength(b)
+			}
 			return typ
 		}
 
@@ -476,18 +765,20 @@ restartParseDataType:
 
 		// otherwise, we don't know.
 		p.errorf("unknown type %s", ident)
+	case itemControlCharacter:
+		// in a type definition, what looks like a control character is a pointer to a
+		// type with a single-letter name.
+		return pointerTypeTo(b, strings.ToLower(p.next().val[1:]), typeDefName)
 	case itemCaret:
 		p.next() // skip ^ token.
 		if p.peek().typ != itemIdentifier {
+			if p.inlinePointerTypes {
+				return p.parseInlinePointerType(b, typeDefName)
+			}
 			p.errorf("expected type after ^, got %s", p.next())
 		}
 
-		ident := p.next().val
-
-		if typ := getBuiltinType(ident); typ != nil {
-			return &PointerType{Type_: typ, name: typeDefName}
-		}
-		return &PointerType{TargetName: ident, name: typeDefName, block: b}
+		return pointerTypeTo(b, p.next().val, typeDefName)
 	case itemOpenParen:
 		return p.parseEnumType(b, typeDefName)
 	case itemPacked:
@@ -530,6 +821,65 @@ restartParseDataType:
 	return nil
 }
 
+// parseStringLength parses the maximum length of a string type.
+//
+//	string-length =
+//	    "[" constant-expression "]" .
+func (p *parser) parseStringLength(b *Block) int {
+	p.next() // skip [ token.
+
+	length, ok := p.parseConstantExpression(b).(*IntegerLiteral)
+	if !ok || length.Value <= 0 {
+		p.errorf("string length must be a positive integer")
+	}
+
+	if p.peek().typ != itemCloseBracket {
+		p.errorf("expected ], got %s", p.next())
+	}
+	p.next()
+
+	return length.Value
+}
+
+// pointerTypeTo returns a pointer type to the type with the provided name.
+func pointerTypeTo(b *Block, ident string, typeDefName string) DataType {
+	if typ := getBuiltinType(ident); typ != nil {
+		return &PointerType{Type_: typ, name: typeDefName}
+	}
+	return &PointerType{TargetName: ident, name: typeDefName, block: b}
+}
+
+// synthesizedTypeName returns a new name for a type definition that is added to the block b.
+// Pascal identifiers can't contain underscores, but names that are already in scope are
+// skipped nonetheless, so that the name never collides with any of the program's identifiers.
+func (p *parser) synthesizedTypeName(b *Block) string {
+	for {
+		p.anonTypeCount++
+		name := fmt.Sprintf("anon_type_%d", p.anonTypeCount)
+		if !b.isIdentifierInScope(name) {
+			return name
+		}
+	}
+}
+
+// parseInlinePointerType parses the type that a pointer type points to, if it is not a
+// type identifier. The type is added to the block as a type definition with a synthesized
+// name, which the returned pointer type then refers to.
+func (p *parser) parseInlinePointerType(b *Block, typeDefName string) DataType {
+	targetName := p.synthesizedTypeName(b)
+
+	targetType := p.parseType(b, targetName)
+	if err := targetType.Resolve(b); err != nil {
+		p.errorf("%v", err)
+	}
+
+	if err := b.addTypeDefinition(&TypeDefinition{Name: targetName, Type: targetType}); err != nil {
+		p.errorf("%v", err)
+	}
+
+	return &PointerType{TargetName: targetName, Type_: targetType.Named(targetName), name: typeDefName, block: b}
+}
+
 // parseEnumType parses an enumerated type.
 //
 //	enumerated-type =
@@ -540,7 +890,12 @@ func (p *parser) parseEnumType(b *Block, typedefName string) *EnumType {
 	}
 	p.next()
 
-	identifierList := p.parseIdentifierList(b)
+	typ := &EnumType{name: typedefName}
+	if p.explicitEnumValues {
+		typ.Identifiers, typ.Values = p.parseEnumIdentifierList(b)
+	} else {
+		typ.Identifiers = p.parseIdentifierList(b)
+	}
 
 	if p.peek().typ != itemCloseParen {
 		p.errorf("expected ), got %s", p.next())
@@ -549,24 +904,69 @@ func (p *parser) parseEnumType(b *Block, typedefName string) *EnumType {
 
 	// TODO: ensure that identifiers in identifier list are indeed unique.
 
-	typ := &EnumType{Identifiers: identifierList, name: typedefName}
-
-	p.addEnumValues(typ.Identifiers, typ)
+	p.addEnumValues(typ)
 
 	return typ
 }
 
-func (p *parser) addEnumValues(identifiers []string, typ DataType) {
-	for identIdx, ident := range identifiers {
+// parseEnumIdentifierList parses the identifiers of an enumerated type where identifiers
+// may have explicit values. Identifiers without explicit value have the value of their
+// predecessor plus one. If no identifier has an explicit value, values is nil.
+//
+//	enum-identifier-list =
+//	    enum-identifier { "," enum-identifier } .
+//	enum-identifier =
+//	    identifier [ "=" constant-expression ] .
+func (p *parser) parseEnumIdentifierList(b *Block) (identifiers []string, values []int) {
+	explicit := false
+	value := 0
+
+	for {
+		if p.peek().typ != itemIdentifier {
+			p.errorf("expected identifier, got %s", p.next())
+		}
+		identifiers = append(identifiers, p.next().val)
+
+		if p.peek().typ == itemEqual {
+			p.next()
+			lit, ok := p.parseConstantExpression(b).(*IntegerLiteral)
+			if !ok {
+				p.errorf("value of enum identifier %s must be an integer", identifiers[len(identifiers)-1])
+			}
+			if len(values) > 0 && lit.Value <= values[len(values)-1] {
+				p.errorf("value of enum identifier %s must be greater than %d", identifiers[len(identifiers)-1], values[len(values)-1])
+			}
+			value = lit.Value
+			explicit = true
+		}
+
+		values = append(values, value)
+		value++
+
+		if p.peek().typ != itemComma {
+			break
+		}
+		p.next()
+	}
+
+	if !explicit {
+		values = nil
+	}
+
+	return identifiers, values
+}
+
+func (p *parser) addEnumValues(typ *EnumType) {
+	for identIdx, ident := range typ.Identifiers {
 		if p.enumValueExists(ident) {
 			p.errorf("enum value %s already exists", ident)
 		}
-		p.addEnumValue(ident, identIdx, typ)
+		p.addEnumValue(ident, typ.Value(identIdx), typ)
 	}
 }
 
-func (p *parser) addEnumValue(ident string, identIdx int, typ DataType) {
-	p.enumValues[ident] = &EnumValue{Name: ident, Value: identIdx, Type: typ}
+func (p *parser) addEnumValue(ident string, value int, typ DataType) {
+	p.enumValues[ident] = &EnumValue{Name: ident, Value: value, Type: typ}
 	p.enumValueList = append(p.enumValueList, ident)
 }
 
@@ -620,6 +1020,9 @@ type Variable struct {
 	Name string
 	Type DataType
 
+	// InitialValue is only set for typed constants, which are variables with an initial value.
+	InitialValue ConstantLiteral
+
 	// the following fields are only set for variables that are looked up from within with statements,
 	// and they indicate that Name and Type describe the field of a record variable of name BelongsTo of
 	// type BelongsToType.
@@ -708,6 +1111,12 @@ type Routine struct {
 	validator        func([]Expression) (DataType, error)
 }
 
+// IsBuiltin returns true if the routine is a builtin procedure or function, rather than
+// a user-declared routine, which may have the same name.
+func (r *Routine) IsBuiltin() bool {
+	return r == FindBuiltinProcedure(r.Name) || r == FindBuiltinFunction(r.Name)
+}
+
 // parseProcedureDeclaration parses a procedure declaration.
 //
 //	procedure-declaration =
@@ -1064,11 +1473,17 @@ func (p *parser) parseStatementSequence(b *Block) []Statement {
 //	statement =
 //		[ label ":" ] (simple-statement | structured-statement) .
 func (p *parser) parseStatement(b *Block) Statement {
+	defer p.enterNesting("statement")()
+
 	var label *string
 	if p.peek().typ == itemUnsignedDigitSequence {
 		labelStr := p.next().val
 		label = &labelStr
 
+		if p.peek().typ == itemAssignment {
+			p.errorf("cannot assign to %s, as it is not a variable", labelStr)
+		}
+
 		if !b.isValidLabel(labelStr) {
 			p.errorf("undeclared label %s", labelStr)
 		}
@@ -1150,40 +1565,63 @@ func (p *parser) parseAssignmentOrProcedureStatement(b *Block, label *string) St
 	identifier := p.next().val
 
 	if p.peek().typ == itemOpenParen {
-		if identifier == "writeln" {
-			return p.parseWrite(b, true, label)
-		} else if identifier == "write" {
-			return p.parseWrite(b, false, label)
+		if identifier == "writeln" || identifier == "write" {
+			p.usedBuiltins[identifier] = true
+			return p.parseWrite(b, identifier == "writeln", label)
 		}
 		proc := b.findProcedure(identifier)
 		if proc == nil {
+			proc = p.findFunctionForStatement(b, identifier)
+		}
+		if proc == nil {
+			if b.findFunction(identifier) != nil {
+				p.parseActualParameterList(b)
+				if p.peek().typ == itemAssignment {
+					p.errorf("cannot assign to result of function call %s", identifier)
+				}
+			}
 			p.errorf("unknown procedure %s", identifier)
 		}
-		actualParameterList := p.parseActualParameterList(b)
+		var actualParameterList []Expression
+		if proc == FindBuiltinProcedure("new") {
+			actualParameterList = p.parseNewParameterList(b)
+		} else {
+			actualParameterList = p.parseActualParameterList(b)
+		}
 		if _, err := p.validateParameters(proc, actualParameterList); err != nil {
 			p.errorf("procedure %s: %v", identifier, err)
 		}
-		return &ProcedureCallStatement{label: label, Name: identifier, ActualParams: actualParameterList, FormalParams: proc.FormalParameters}
+		p.recordBuiltinCall(proc)
+		return &ProcedureCallStatement{label: label, Name: identifier, ActualParams: actualParameterList, FormalParams: proc.FormalParameters, Routine: proc}
 	}
 
 	if identifier == "writeln" {
+		p.usedBuiltins[identifier] = true
+		p.verifyStandardOutput(identifier)
 		return &WriteStatement{label: label, AppendNewLine: true}
 	} else if identifier == "write" {
 		p.errorf("write needs at least one parameter")
 	}
 
 	proc := b.findProcedure(identifier)
+	if proc == nil && b.findFunctionForAssignment(identifier) == nil {
+		// within a function, its name without parameters refers to its return value.
+		proc = p.findFunctionForStatement(b, identifier)
+	}
 	if proc != nil {
 		if _, err := p.validateParameters(proc, []Expression{}); err != nil {
 			p.errorf("procedure %s: %v", identifier, err)
 		}
-		return &ProcedureCallStatement{label: label, Name: identifier, FormalParams: proc.FormalParameters}
+		p.recordBuiltinCall(proc)
+		return &ProcedureCallStatement{label: label, Name: identifier, FormalParams: proc.FormalParameters, Routine: proc}
 	}
 
 	var lexpr Expression
 
 	if funcDecl := b.findFunctionForAssignment(identifier); funcDecl != nil {
-		lexpr = &VariableExpr{Name: identifier, Type_: funcDecl.ReturnType, IsReturnValue: true}
+		lexpr = p.parseVariableSelectors(b, &VariableExpr{Name: identifier, Type_: funcDecl.ReturnType, IsReturnValue: true})
+	} else if b.findFormalParameter(identifier) == nil && b.findVariable(identifier) == nil && b.findConstantDeclaration(identifier) != nil {
+		p.errorf("cannot assign to constant %s", identifier)
 	} else {
 		lexpr = p.parseVariable(b, identifier)
 	}
@@ -1214,6 +1652,11 @@ func (p *parser) parseAssignmentOrProcedureStatement(b *Block, label *string) St
 		if !typesCompatibleForAssignment(lexpr.Type(), rexpr.Type()) {
 			p.errorf("incompatible types: got %s, expected %s", rexpr.Type().TypeString(), lexpr.Type().TypeString())
 		}
+		if st, ok := lexpr.Type().(*SubrangeType); ok {
+			if value, str, ok := constantOrdinalValue(b, rexpr); ok && !st.within(value) {
+				p.errorf("assignment: %s is outside subrange type %s", str, st.TypeString())
+			}
+		}
 		return &AssignmentStatement{label: label, LeftExpr: lexpr, RightExpr: rexpr}
 	}
 
@@ -1222,6 +1665,22 @@ func (p *parser) parseAssignmentOrProcedureStatement(b *Block, label *string) St
 	return nil
 }
 
+// findFunctionForStatement returns the function with the provided name if function
+// calls are allowed as statements, discarding the result. Builtin functions can't be
+// called as statements.
+func (p *parser) findFunctionForStatement(b *Block, name string) *Routine {
+	if !p.functionCallStatements {
+		return nil
+	}
+
+	funcDecl := b.findFunction(name)
+	if funcDecl == nil || funcDecl == FindBuiltinFunction(name) {
+		return nil
+	}
+
+	return funcDecl
+}
+
 func (p *parser) stringToCharLiteralExpr(expr Expression) Expression {
 	if se, ok := expr.(*StringExpr); ok {
 		return &CharExpr{
@@ -1489,6 +1948,9 @@ func (p *parser) parseWithStatement(b *Block, label *string) Statement {
 		expr := p.parseExpression(b)
 
 		if !expr.IsVariableExpr() {
+			if funcCall, ok := expr.(*FunctionCallExpr); ok {
+				p.errorf("result of function call %s is not a record variable", funcCall.Name)
+			}
 			p.errorf("not a variable access expression")
 		}
 
@@ -1499,7 +1961,7 @@ func (p *parser) parseWithStatement(b *Block, label *string) Statement {
 
 		recordExpressions = append(recordExpressions, expr)
 
-		for _, field := range recType.Fields {
+		for _, field := range recType.allFields() {
 			withBlock.Variables = append(withBlock.Variables, &Variable{
 				Name:          field.Identifier,
 				Type:          field.Type,
@@ -1572,6 +2034,43 @@ func (p *parser) parseActualParameterList(b *Block) []Expression {
 	return params
 }
 
+// parseNewParameterList parses the actual parameter list of the builtin procedure new.
+// Unlike other actual parameters, the name of a function that is currently being declared
+// refers to its return value as first parameter, so that pointers can be allocated as
+// return values.
+func (p *parser) parseNewParameterList(b *Block) []Expression {
+	if p.peek().typ != itemOpenParen {
+		p.errorf("expected (, got %s", p.next())
+	}
+	p.next()
+
+	var expr Expression
+
+	if p.peek().typ == itemIdentifier {
+		if funcDecl := b.findFunctionForAssignment(p.peek().val); funcDecl != nil {
+			expr = p.parseVariableSelectors(b, &VariableExpr{Name: p.next().val, Type_: funcDecl.ReturnType, IsReturnValue: true})
+		}
+	}
+
+	if expr == nil {
+		expr = p.parseExpression(b)
+	}
+
+	params := []Expression{expr}
+
+	for p.peek().typ == itemComma {
+		p.next()
+		params = append(params, p.parseExpression(b))
+	}
+
+	if p.peek().typ != itemCloseParen {
+		p.errorf("expected ), got %s", p.next())
+	}
+	p.next()
+
+	return params
+}
+
 // parseExpression parses an expression.
 //
 //	expression =
@@ -1652,6 +2151,13 @@ func (p *parser) parseSimpleExpression(b *Block) *SimpleExpr {
 			if !IsBooleanType(simpleExpr.First.Type()) {
 				p.errorf("can't use or with %s", simpleExpr.First.Type().TypeString())
 			}
+		} else if operator == OperatorSymmetricDifference {
+			if p.strictISO {
+				p.errorf("%s operator is not part of ISO Pascal", operator)
+			}
+			if !isSetType(simpleExpr.First.Type()) {
+				p.errorf("can't use %s operator with %s", operator, simpleExpr.First.Type().TypeString())
+			}
 		} else {
 			if !isIntegerType(simpleExpr.First.Type()) &&
 				!isRealType(simpleExpr.First.Type()) &&
@@ -1744,6 +2250,7 @@ func (p *parser) parseTerm(b *Block) *TermExpr {
 func (p *parser) parseFactor(b *Block) Expression {
 	p.logger.Printf("Parsing factor")
 	defer p.logger.Printf("Finished parsing factor")
+	defer p.enterNesting("expression")()
 
 	switch p.peek().typ {
 	case itemIdentifier:
@@ -1757,7 +2264,8 @@ func (p *parser) parseFactor(b *Block) Expression {
 				if err != nil {
 					p.errorf("function %s: %v", ident, err)
 				}
-				return &FunctionCallExpr{Name: ident, ActualParams: params, Type_: returnType, FormalParams: funcDecl.FormalParameters}
+				p.recordBuiltinCall(funcDecl)
+				return &FunctionCallExpr{Name: ident, ActualParams: params, Type_: returnType, FormalParams: funcDecl.FormalParameters, Routine: funcDecl}
 			}
 
 			if len(funcDecl.FormalParameters) > 0 { // function has formal parameter which are not provided -> it's a functional-parameter
@@ -1768,7 +2276,8 @@ func (p *parser) parseFactor(b *Block) Expression {
 			if err != nil {
 				p.errorf("function %s: %v", ident, err)
 			}
-			return &FunctionCallExpr{Name: ident, Type_: returnType}
+			p.recordBuiltinCall(funcDecl)
+			return &FunctionCallExpr{Name: ident, Type_: returnType, Routine: funcDecl}
 
 		}
 		if constDecl := b.findConstantDeclaration(ident); constDecl != nil {
@@ -1777,12 +2286,17 @@ func (p *parser) parseFactor(b *Block) Expression {
 		if idx, typ := b.findEnumValue(ident); typ != nil {
 			return &EnumValueExpr{Name: ident, Value: idx, Type_: typ}
 		}
+		if p.typeCasts && p.peek().typ == itemOpenParen {
+			if typ := b.findType(ident); typ != nil {
+				return p.parseTypeCast(b, ident, typ)
+			}
+		}
 
 		return p.parseVariable(b, ident)
 	case itemSign:
 		sign := p.next().val
 		return p.parseNumber(sign == "-")
-	case itemUnsignedDigitSequence:
+	case itemUnsignedDigitSequence, itemDot:
 		return p.parseNumber(false)
 	case itemStringLiteral:
 		p.logger.Printf("parseFactor: got string literal %s", p.peek())
@@ -1791,6 +2305,8 @@ func (p *parser) parseFactor(b *Block) Expression {
 			return &CharExpr{Value: se.Value[0]}
 		}
 		return se
+	case itemControlCharacter:
+		return &CharExpr{Value: controlCharacter(p.next().val)}
 	case itemOpenBracket:
 		return p.parseSet(b)
 	case itemNil:
@@ -1812,6 +2328,28 @@ func (p *parser) parseFactor(b *Block) Expression {
 	return nil
 }
 
+// parseTypeCast parses the conversion of an ordinal value to another ordinal type,
+// where the type name is used like a function, e.g. integer(ch) or char(n).
+//
+//	type-cast =
+//		type-identifier "(" expression ")" .
+func (p *parser) parseTypeCast(b *Block, typeName string, typ DataType) Expression {
+	if !isOrdinalType(typ) {
+		p.errorf("can't convert to %s, as it is not an ordinal type", typeName)
+	}
+
+	params := p.parseActualParameterList(b)
+	if len(params) != 1 {
+		p.errorf("conversion to %s requires exactly 1 argument, got %d arguments instead", typeName, len(params))
+	}
+
+	if !isOrdinalType(params[0].Type()) {
+		p.errorf("can't convert %s to %s, as it is not an ordinal type", params[0].Type().TypeString(), typeName)
+	}
+
+	return &TypeCastExpr{Expr: params[0], Type_: typ}
+}
+
 // parseVariable parses a variable.
 //
 //	variable =
@@ -1831,6 +2369,18 @@ func (p *parser) parseVariable(b *Block, ident string) Expression {
 		p.errorf("unknown identifier %s", ident)
 	}
 
+	return p.parseVariableSelectors(b, expr)
+}
+
+// controlCharacter returns the control character that is denoted by ^ followed by a
+// letter, e.g. 13 for ^M.
+func controlCharacter(s string) byte {
+	return s[1] & 0x1f
+}
+
+// parseVariableSelectors parses the dereferences, indexes and field designators that
+// follow a variable, and applies them to the variable expression.
+func (p *parser) parseVariableSelectors(b *Block, expr Expression) Expression {
 	cont := true
 
 	for cont {
@@ -1880,11 +2430,16 @@ func (p *parser) parseVariable(b *Block, ident string) Expression {
 //	real-number =
 //		digit-sequence "." [ digit-sequence ] [ scale-factor ] |
 //		digit-sequence scale-factor .
+//
+// Unlike the EBNF, a real number without any digits before the "." (e.g. .5) is also accepted.
 func (p *parser) parseNumber(minus bool) Expression {
 	p.logger.Printf("Parsing number")
 
-	unsignedDigitSequence := p.next().val
-	if p.peek().typ == itemDot || (p.peek().typ == itemIdentifier && strings.ToLower(p.peek().val) == "e") {
+	unsignedDigitSequence := ""
+	if p.peek().typ != itemDot {
+		unsignedDigitSequence = p.next().val
+	}
+	if p.peek().typ == itemDot || isScaleFactorStart(p.peek()) {
 		scaleFactor := 0
 		afterComma := ""
 		if p.peek().typ == itemDot {
@@ -1892,15 +2447,14 @@ func (p *parser) parseNumber(minus bool) Expression {
 			if p.peek().typ == itemUnsignedDigitSequence { // N.B. EBNF says digit-sequence here, but this doesn't make sense.
 				afterComma = p.next().val
 			}
-			if p.peek().typ == itemIdentifier && strings.ToLower(p.peek().val) == "e" {
-				p.next()
+			if isScaleFactorStart(p.peek()) {
 				scaleFactor = p.parseScaleFactor()
 			}
-		} else if p.peek().typ == itemIdentifier && strings.ToLower(p.peek().val) == "e" { // TODO: change lexing so that e is returned as its own token in this particular instance.
-			p.next()
-			scaleFactor = p.parseScaleFactor()
 		} else {
-			p.errorf("expected either . or E, but got %v instead", p.peek())
+			scaleFactor = p.parseScaleFactor()
+		}
+		if unsignedDigitSequence == "" && afterComma == "" {
+			p.errorf("expected digit sequence after ., got %v instead", p.peek())
 		}
 		p.logger.Printf("parseNumber: parsed float")
 		return &RealExpr{Minus: minus, BeforeComma: unsignedDigitSequence, AfterComma: afterComma, ScaleFactor: scaleFactor}
@@ -1916,11 +2470,30 @@ func (p *parser) parseNumber(minus bool) Expression {
 	return &IntegerExpr{int(intValue)}
 }
 
+// isScaleFactorStart returns true if the item starts a scale factor. As the lexer
+// doesn't know about scale factors, the "e" is returned as part of an identifier,
+// which may also already contain the digit sequence if no sign was provided (e.g. 5e3).
+func isScaleFactorStart(it item) bool {
+	return it.typ == itemIdentifier && it.val[0] == 'e' && strings.Trim(it.val[1:], "0123456789") == ""
+}
+
 // parseScaleFactor parses a scale factor.
 //
 //	scale-factor =
 //		("E" | "e") [ sign ] digit-sequence .
 func (p *parser) parseScaleFactor() int {
+	if !isScaleFactorStart(p.peek()) {
+		p.errorf("expected scale factor, got %v instead", p.peek())
+	}
+
+	if e := p.next().val; len(e) > 1 {
+		scaleFactor, err := strconv.ParseInt(e[1:], 10, 64)
+		if err != nil {
+			p.errorf("failed to parse %s as integer: %v", e[1:], err)
+		}
+		return int(scaleFactor)
+	}
+
 	minus := false
 	if typ := p.peek().typ; typ == itemSign {
 		minus = p.next().val == "-"
@@ -2088,7 +2661,9 @@ func (p *parser) parseArrayType(b *Block, packed bool) *ArrayType {
 
 	elementType := p.parseType(b, "")
 
-	for isArrayType(elementType) {
+	// array[a] of array[b] of t is equivalent to array[a, b] of t, but only if both
+	// arrays are either packed or not packed.
+	for isArrayType(elementType) && elementType.(*ArrayType).Packed == packed {
 		arrType := elementType.(*ArrayType)
 		indexTypes = append(indexTypes, arrType.IndexTypes...)
 		elementType = arrType.ElementType
@@ -2137,11 +2712,11 @@ func (p *parser) parseSimpleType(b *Block) DataType {
 //	subrange-type =
 //		lower-bound ".." upper-bound .
 //	lower-bound =
-//		constant .
+//		constant-expression .
 //	upper-bound =
-//		constant .
+//		constant-expression .
 func (p *parser) parseSubrangeType(b *Block) DataType {
-	lowerBound := p.parseConstant(b)
+	lowerBound := p.parseConstantExpression(b)
 	var (
 		lowerValue int
 		upperValue int
@@ -2168,7 +2743,7 @@ func (p *parser) parseSubrangeType(b *Block) DataType {
 	}
 	p.next()
 
-	upperBound := p.parseConstant(b)
+	upperBound := p.parseConstantExpression(b)
 
 	switch ub := upperBound.(type) {
 	case *IntegerLiteral:
@@ -2220,7 +2795,7 @@ func isPossiblyConstant(b *Block, it item) bool {
 		return true
 	}
 
-	return it.typ == itemSign || it.typ == itemUnsignedDigitSequence || it.typ == itemStringLiteral
+	return it.typ == itemSign || it.typ == itemUnsignedDigitSequence || it.typ == itemStringLiteral || it.typ == itemControlCharacter
 }
 
 func (p *parser) parseConstantWithoutSign(b *Block, minus bool) ConstantLiteral {
@@ -2239,7 +2814,7 @@ func (p *parser) parseConstantWithoutSign(b *Block, minus bool) ConstantLiteral
 
 			v = &EnumValueLiteral{Symbol: constantName, Value: idx, Type: typ}
 		}
-	} else if p.peek().typ == itemUnsignedDigitSequence {
+	} else if p.peek().typ == itemUnsignedDigitSequence || p.peek().typ == itemDot {
 		number := p.parseNumber(false) // negation will be done later on.
 		switch n := number.(type) {
 		case *IntegerExpr:
@@ -2254,6 +2829,8 @@ func (p *parser) parseConstantWithoutSign(b *Block, minus bool) ConstantLiteral
 		} else {
 			v = sl
 		}
+	} else if p.peek().typ == itemControlCharacter {
+		v = &CharLiteral{Value: controlCharacter(p.next().val)}
 	} else {
 		p.errorf("got unexpected %s while parsing constant", p.peek())
 	}
@@ -2403,12 +2980,20 @@ func (p *parser) parseVariantPart(b *Block, packed bool) (field *RecordVariantFi
 		Type:     typeDef.Named(typeIdentifier),
 	}
 
+	seenLabels := make(map[string]bool)
+
 	for {
 		if !isPossiblyConstant(b, p.peek()) { // variant always starts with constant.
 			break
 		}
 
 		variant := p.parseVariant(b, packed)
+		for _, label := range variant.CaseLabels {
+			if seenLabels[label.String()] {
+				p.errorf("duplicate variant case label %s", label.String())
+			}
+			seenLabels[label.String()] = true
+		}
 		field.Variants = append(field.Variants, variant)
 
 		if p.peek().typ != itemSemicolon {
@@ -2508,7 +3093,7 @@ func (p *parser) validateParameters(proc *Routine, actualParams []Expression) (r
 
 		if proc.FormalParameters[idx].VariableParameter {
 			if !actualParams[idx].IsVariableExpr() {
-				return nil, fmt.Errorf("parameter %s is a variable parameter, but an actual parameter other than variable was provided",
+				return nil, fmt.Errorf("argument for var parameter %s must be a variable",
 					proc.FormalParameters[idx].Name)
 			}
 
@@ -2544,6 +3129,14 @@ func (p *parser) parseIndexedVariableExpr(b *Block, expr Expression) *IndexedVar
 		return &IndexedVariableExpr{Expr: expr, IndexExprs: indexes, Type_: &CharType{}}
 	}
 
+	return p.indexArray(expr, indexes)
+}
+
+// indexArray checks that the index expressions match the index types of the array
+// that expr is of, and returns the indexed variable expression. As arrays of arrays
+// that differ in packing are not collapsed into a single array type, index expressions
+// beyond the array's dimensions apply to its element type.
+func (p *parser) indexArray(expr Expression, indexes []Expression) *IndexedVariableExpr {
 	arrType, ok := expr.Type().(*ArrayType)
 	if !ok {
 		p.errorf("expression is not array")
@@ -2551,10 +3144,15 @@ func (p *parser) parseIndexedVariableExpr(b *Block, expr Expression) *IndexedVar
 
 	// TODO: support situation where fewer index expressions mean that an array of fewer dimensions is returned.
 
-	if len(indexes) == 0 || len(arrType.IndexTypes) < len(indexes) {
+	if len(indexes) == 0 || (len(arrType.IndexTypes) < len(indexes) && !isArrayType(arrType.ElementType)) {
 		p.errorf("array has %d dimensions but %d index expressions were provided", len(arrType.IndexTypes), len(indexes))
 	}
 
+	var remainingIndexes []Expression
+	if len(arrType.IndexTypes) < len(indexes) {
+		indexes, remainingIndexes = indexes[:len(arrType.IndexTypes)], indexes[len(arrType.IndexTypes):]
+	}
+
 	for idx, idxType := range arrType.IndexTypes {
 		if idx >= len(indexes) {
 			break
@@ -2564,7 +3162,11 @@ func (p *parser) parseIndexedVariableExpr(b *Block, expr Expression) *IndexedVar
 		}
 	}
 
-	return &IndexedVariableExpr{Expr: expr, IndexExprs: indexes, Type_: arrType.ElementType}
+	indexedExpr := &IndexedVariableExpr{Expr: expr, IndexExprs: indexes, Type_: arrType.ElementType}
+	if len(remainingIndexes) > 0 {
+		return p.indexArray(indexedExpr, remainingIndexes)
+	}
+	return indexedExpr
 }
 
 // parseWrite parses a write statement.
@@ -2594,6 +3196,16 @@ func (p *parser) parseWrite(b *Block, ln bool, label *string) *WriteStatement {
 		p.next()
 
 		param := p.parseExpression(b)
+
+		if stmt.FileVar != nil && !IsTextType(stmt.FileVar.Type()) {
+			elemType := stmt.FileVar.Type().(*FileType).ElementType
+			if !typesCompatibleForAssignment(elemType, param.Type()) {
+				p.errorf("can't write %s to %s", param.Type().TypeString(), stmt.FileVar.Type().TypeString())
+			}
+			stmt.ActualParams = append(stmt.ActualParams, param)
+			continue
+		}
+
 		p.verifyWriteParameter(param, ln)
 
 		width, decimalPlaces := p.parseWritelnFormat(param, b)
@@ -2605,9 +3217,32 @@ func (p *parser) parseWrite(b *Block, ln bool, label *string) *WriteStatement {
 	}
 	p.next()
 
+	if stmt.FileVar == nil {
+		name := "write"
+		if ln {
+			name = "writeln"
+		}
+		p.verifyStandardOutput(name)
+	}
+
 	return stmt
 }
 
+// verifyStandardOutput reports an error in strict ISO mode if the program writes to the
+// standard output, identified by the name of the writing procedure, without providing
+// output in the program heading.
+func (p *parser) verifyStandardOutput(name string) {
+	if !p.strictISO {
+		return
+	}
+	for _, file := range p.programFiles {
+		if file == "output" {
+			return
+		}
+	}
+	p.errorf("%s to standard output requires output in the program heading", name)
+}
+
 func (p *parser) parseWritelnFormat(expr Expression, b *Block) (widthExpr Expression, decimalPlacesExpr Expression) {
 	if p.peek().typ == itemColon {
 		p.next()
@@ -2666,5 +3301,9 @@ func (p *parser) verifyWriteParameter(expr Expression, ln bool) {
 		return
 	}
 
+	if p.writableSets && isSetType(typ) {
+		return
+	}
+
 	p.errorf("can't use variables of type %s with %s", typ.TypeString(), funcName)
 }
@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -43,6 +45,55 @@ func TestParserSuccesses(t *testing.T) {
 			begin
 			end.`,
 		},
+		{
+			"function returning newly allocated pointer",
+			`program test;
+			type pinteger = ^integer;
+			var p : pinteger;
+			function newint(v : integer) : pinteger;
+			begin
+				new(newint);
+				newint^ := v
+			end;
+			begin
+				p := newint(23)
+			end.`,
+		},
+		{
+			"enum subrange variable",
+			`program test;
+			type primary = (red, green, blue);
+				warm = red..green;
+			var c : warm;
+				p : primary;
+			begin
+				c := red;
+				c := green;
+				p := c;
+				c := p
+			end.`,
+		},
+		{
+			"page on text files",
+			`program test(output);
+			var f : text;
+			begin
+				rewrite(f);
+				page(f);
+				page(output);
+				page
+			end.`,
+		},
+		{
+			"procedure named new",
+			`program test;
+			procedure new(a, b : integer);
+			begin
+			end;
+			begin
+				new(1, 2)
+			end.`,
+		},
 		{
 			"multiple const declarations",
 			`program test;
@@ -1384,6 +1435,272 @@ func TestParserSuccesses(t *testing.T) {
 			end.
 			`,
 		},
+		{
+			"real literals without digits after or before the dot, and with scale factor only",
+			`program test;
+
+			const a = 5.;
+				b = .5;
+				c = 5e3;
+
+			var r : real;
+
+			begin
+				r := -.25;
+				r := 1.5E+2;
+				r := 5.e3
+			end.
+			`,
+		},
+		{
+			"enum constant expressions as array index",
+			`program test;
+
+			type colour = (red, green, blue);
+
+			var a : array[colour] of integer;
+				b : array[red..green] of integer;
+
+			begin
+				a[green] := 1;
+				a[succ(red)] := 2;
+				b[pred(green)] := 3
+			end.
+			`,
+		},
+		{
+			"program followed by whitespace and comments",
+			`program test;
+			begin
+			end.
+			{ trailing comment }
+			(* another trailing comment *)
+			`,
+		},
+		{
+			"assignment between subrange set and integer set",
+			`program test;
+
+			type small = 1..10;
+
+			var s : set of small;
+				t : set of integer;
+
+			begin
+				s := [1, 3, 5];
+				t := s;
+				s := t + [7]
+			end.
+			`,
+		},
+		{
+			"case statement over enum subrange",
+			`program test;
+
+			type colour = (red, green, blue, yellow);
+				primary = red..blue;
+
+			var p : primary;
+
+			begin
+				p := green;
+				case p of
+					red: writeln('red');
+					green, blue: writeln('green or blue')
+				end
+			end.
+			`,
+		},
+		{
+			"case statement over integer subrange",
+			`program test;
+
+			var i : 1..10;
+
+			begin
+				i := 3;
+				case i of
+					1, 2: writeln('small');
+					3: writeln('three')
+				end
+			end.
+			`,
+		},
+		{
+			"comparison of pointers to the same named type",
+			`program test;
+
+			type nodeptr = ^node;
+				node = record
+					value : integer;
+					next : nodeptr
+				end;
+
+			var p1, p2 : ^node;
+				p3 : nodeptr;
+
+			begin
+				if p1 = p2 then
+					writeln('equal');
+				if p1 <> p3 then
+					writeln('different');
+				if p3^.next = p1 then
+					writeln('successor')
+			end.
+			`,
+		},
+		{
+			"comparison of pointer with nil",
+			`program test;
+
+			type node = record
+					value : integer
+				end;
+
+			var p : ^node;
+
+			begin
+				if p <> nil then
+					writeln('not nil');
+				if nil = p then
+					writeln('nil')
+			end.
+			`,
+		},
+		{
+			"math functions with parenthesized subexpressions as arguments",
+			`program test;
+
+			var a, b, c, r : real;
+				i, j : integer;
+
+			begin
+				r := sqrt((a + b) * c);
+				r := sqrt((a + b));
+				i := abs((i - j));
+				i := sqr((i + j) * j);
+				r := sqr((a - b)) + abs(-(a * c))
+			end.
+			`,
+		},
+		{
+			"boolean ordinal functions",
+			`program test;
+
+			var b : boolean;
+				n : integer;
+
+			begin
+				n := ord(b) + 1;
+				n := 2 * ord(succ(false)) - ord(pred(b));
+				b := succ(false) and not pred(true);
+				b := pred(true) or b;
+				b := ord(succ(b)) > n
+			end.`,
+		},
+		{
+			"comparison of enum subrange variable with enum constants",
+			`program test;
+
+			type colour = (red, green, blue, yellow);
+				primary = red..blue;
+
+			var c : primary;
+
+			begin
+				c := green;
+				if c = red then
+					writeln('red');
+				if yellow <> c then
+					writeln('not yellow');
+				if (c > red) and (c <= blue) then
+					writeln('green or blue')
+			end.`,
+		},
+		{
+			"with statement accessing tag field and variant fields",
+			`program test;
+
+			var c : record
+					n : integer;
+					case tag : integer of
+						1: ( i : integer );
+						2: ( r : real; s : string );
+				end;
+
+			begin
+				with c do
+				begin
+					n := 1;
+					tag := 2;
+					r := 2.5;
+					s := 'variant';
+					if tag = 2 then
+						i := n
+				end
+			end.`,
+		},
+		{
+			"reset and rewrite of standard files",
+			`program test(input, output);
+
+			var i : integer;
+
+			begin
+				reset(input);
+				rewrite(output);
+				readln(i);
+				writeln(i)
+			end.`,
+		},
+		{
+			"in with subrange operand",
+			`program test;
+
+			type digit = 0..9;
+
+			var n : 1..100;
+				d : digit;
+				s : set of digit;
+
+			begin
+				s := [1, 3, 5];
+				if n in [1..10] then
+					writeln('small');
+				if d in s then
+					writeln('odd');
+				if d in [0..4] then
+					writeln('low')
+			end.`,
+		},
+		{
+			"with over variable, field and indexed records",
+			`program test;
+
+			type rec = record
+					a : integer
+				end;
+				outer = record
+					inner : rec
+				end;
+
+			var r : rec;
+				o : outer;
+				arr : array[1..3] of rec;
+				p : ^rec;
+
+			begin
+				new(p);
+				with r do
+					a := 1;
+				with o.inner do
+					a := 2;
+				with arr[2] do
+					a := 3;
+				with p^ do
+					a := 4
+			end.`,
+		},
 	}
 
 	for idx, testEntry := range testData {
@@ -1409,6 +1726,11 @@ func TestParserErrors(t *testing.T) {
 			`program test;
 			begin end;`,
 		},
+		{
+			"program is followed by trailing content",
+			`unexpected identifier "extra" after end of program`,
+			`program t; begin end. extra`,
+		},
 		{
 			"program doesn't start with program",
 			`expected program, got "for"`,
@@ -1416,7 +1738,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"program isn't defined by identifier",
-			`expected identifier, got "23"`,
+			`expected identifier, got number "23"`,
 			"program 23; begin end.",
 		},
 		{
@@ -1431,7 +1753,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"declaration label is not an unsigned digit sequence",
-			`expected number, got "foo"`,
+			`expected number, got identifier "foo"`,
 			"program test; label foo; begin end.",
 		},
 		{
@@ -1446,7 +1768,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"type declaration with pointer to integer literal",
-			`expected type after ^, got "123"`,
+			`expected type after ^, got number "123"`,
 			"program test; type foo = ^123; begin end.",
 		},
 		{
@@ -1456,7 +1778,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"set type declaration with missing of keyword",
-			`expected of after set, got "integer"`,
+			`expected of after set, got identifier "integer"`,
 			"program test; type foo = set integer; begin end.",
 		},
 		{
@@ -1471,12 +1793,12 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"enum type declaration with integer literal",
-			`expected identifier, got "456"`,
+			`expected identifier, got number "456"`,
 			"program test; type foo = (456, bar, quux); begin end.",
 		},
 		{
 			"enum type declaration with integer literal (2)",
-			`expected identifier, got "678"`,
+			`expected identifier, got number "678"`,
 			"program test; type foo = (bar, 678, quux); begin end.",
 		},
 		{
@@ -1615,7 +1937,7 @@ func TestParserErrors(t *testing.T) {
 
 		{
 			"left expression not followed by assignment",
-			`unexpected token "writeln" in statement`,
+			`unexpected token identifier "writeln" in statement`,
 			`program test;
 			var x : integer;
 			begin
@@ -1624,7 +1946,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"procedure with variable parameter inside sub expression",
-			"parameter y is a variable parameter, but an actual parameter other than variable was provided",
+			"argument for var parameter y must be a variable",
 			`program test;
 
 			var x : integer;
@@ -1667,7 +1989,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"unterminated string",
-			`unexpected EOF while parsing factor`,
+			`unexpected end-of-file while parsing factor`,
 			`program test;
 
 			begin
@@ -1692,7 +2014,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"invalid label declaration",
-			`expected number, got "foobar"`,
+			`expected number, got identifier "foobar"`,
 			`program test;
 
 			label foobar;
@@ -1784,7 +2106,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"constant definition without = ",
-			`expected =, got "23"`,
+			`expected =, got number "23"`,
 			`program test;
 
 			const foo 23;
@@ -1863,7 +2185,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"file type without of",
-			`expected of after file, got "integer"`,
+			`expected of after file, got identifier "integer"`,
 			`program test;
 
 			type foo = file integer;
@@ -1885,7 +2207,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"invalid procedure heading",
-			`expected procedure identifier, got "123"`,
+			`expected procedure identifier, got number "123"`,
 			`program test;
 
 			procedure 123;
@@ -1911,7 +2233,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"invalid formal parameter missing the : after the identifier list",
-			`expected :, got "integer"`,
+			`expected :, got identifier "integer"`,
 			`program test;
 
 			procedure foo(a, b, c integer);
@@ -1924,7 +2246,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"invalid formal procedural parameter",
-			`expected procedure name, got "123" instead`,
+			`expected procedure name, got number "123" instead`,
 			`program test;
 
 			procedure foo(procedure 123);
@@ -1937,7 +2259,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"invalid formal functional parameter",
-			`expected function name, got "123" instead`,
+			`expected function name, got number "123" instead`,
 			`program test;
 
 			procedure foo(function 123 : integer);
@@ -1976,7 +2298,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"invalid function heading",
-			`expected function identifier, got "123"`,
+			`expected function identifier, got number "123"`,
 			`program test;
 
 			function 123 : integer;
@@ -1989,7 +2311,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"label that is not followed by :",
-			`expected : after label, got "writeln"`,
+			`expected : after label, got identifier "writeln"`,
 			`program test;
 
 			label 123;
@@ -2001,7 +2323,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"invalid label in goto",
-			`expected label after goto, got "foo"`,
+			`expected label after goto, got identifier "foo"`,
 			`program test;
 
 			label 123;
@@ -2094,7 +2416,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"for statement without assignment",
-			`expected :=, got "1"`,
+			`expected :=, got number "1"`,
 			`program test;
 
 			var x : integer;
@@ -2107,7 +2429,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"for statement without do",
-			`expected do, got "writeln"`,
+			`expected do, got identifier "writeln"`,
 			`program test;
 
 			var x : integer;
@@ -2133,7 +2455,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"if statement without then",
-			`expected then, got "writeln"`,
+			`expected then, got identifier "writeln"`,
 			`program test;
 
 			var x : boolean;
@@ -2146,7 +2468,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"case statement without of",
-			`expected of, got "1" instead`,
+			`expected of, got number "1" instead`,
 			`program test;
 
 			var x : integer;
@@ -2160,7 +2482,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"case statement without of",
-			`expected of, got "1" instead`,
+			`expected of, got number "1" instead`,
 			`program test;
 
 			var x : integer;
@@ -2218,7 +2540,7 @@ func TestParserErrors(t *testing.T) {
 		},
 		{
 			"with statement with invalid record variable",
-			`expected identifier of record variable, got "123" instead`,
+			`expected identifier of record variable, got number "123" instead`,
 			`program test;
 
 			var x : integer;
@@ -2287,6 +2609,22 @@ func TestParserErrors(t *testing.T) {
 			end.
 			`,
 		},
+		{
+			"in operator with enum value and set of different enum",
+			`type colour does not match set type shape`,
+			`program test;
+
+			type colour = (red, green);
+				shape = (circle, square);
+
+			var x : boolean;
+				c : colour;
+
+			begin
+				x := c in [circle, square]
+			end.
+			`,
+		},
 		{
 			"in operator set type of wrong type",
 			`sets require an ordinal type, got real instead`,
@@ -2441,35 +2779,1600 @@ func TestParserErrors(t *testing.T) {
 				dispose(x)
 			end.`,
 		},
-	}
+		{
+			"index enum-indexed array with value of different enum",
+			`array dimension 0 is of type colour, but index expression type suit was provided`,
+			`program test;
 
-	for idx, tt := range testData {
-		t.Run(tt.Name, func(t *testing.T) {
-			p, err := Parse(fmt.Sprintf("test_%d.pas", idx), tt.Code)
-			if err == nil {
-				t.Errorf("Parsing code unexpectedly didn't return error")
-			} else if !strings.Contains(err.Error(), tt.ExpectedError) {
-				t.Logf("expected error = %s", tt.ExpectedError)
-				t.Errorf("Parsing returned error, but didn't contain expected error message")
-			}
-			_ = p
-			t.Logf("error = %v", err)
-		})
-	}
-}
+			type colour = (red, green, blue);
+				suit = (club, diamond, heart, spade);
 
-func TestParserOnTranspileSet(t *testing.T) {
-	pascalFiles, err := filepath.Glob("../pas2go/testdata/*.pas")
-	require.NoError(t, err)
+			var a : array[colour] of integer;
 
-	for _, pascalFile := range pascalFiles {
-		t.Run(filepath.Base(pascalFile), func(t *testing.T) {
-			fileContent, err := ioutil.ReadFile(pascalFile)
-			require.NoError(t, err)
+			begin
+				a[heart] := 1
+			end.`,
+		},
+		{
+			"index enum subrange-indexed array with value of different enum",
+			`array dimension 0 is of type red..green, but index expression type suit was provided`,
+			`program test;
 
-			ast, err := Parse(pascalFile, string(fileContent))
-			require.NoError(t, err, "parsing source file failed")
-			require.NotNil(t, ast, "parsing source succeeded, but ast is nil")
-		})
-	}
+			type colour = (red, green, blue);
+				suit = (club, diamond, heart, spade);
+
+			var a : array[red..green] of integer;
+
+			begin
+				a[club] := 1
+			end.`,
+		},
+		{
+			"constant mod with zero",
+			`mod requires a positive right operand, got 0 instead`,
+			`program test;
+
+			const r = 7 mod 0;
+
+			begin
+			end.`,
+		},
+		{
+			"function result passed as variable parameter",
+			"argument for var parameter y must be a variable",
+			`program test;
+
+			var x : integer;
+
+			function f(a : integer) : integer;
+			begin
+				f := a + 1
+			end;
+
+			procedure quux(var y : integer);
+			begin
+				y := 3
+			end;
+
+			begin
+				x := 2;
+				quux(f(x))
+			end.`,
+		},
+		{
+			"assign with non-string file name",
+			"assign: second argument has to be of string type, got integer instead",
+			`program test;
+
+			var f : text;
+
+			begin
+				assign(f, 42)
+			end.`,
+		},
+		{
+			"close with non-file argument",
+			"close: need exactly 1 argument of file type, got integer instead",
+			`program test;
+
+			var i : integer;
+
+			begin
+				close(i)
+			end.`,
+		},
+		{
+			"seek on text file",
+			"seek: argument has to be of typed file type, got text file instead",
+			`program test;
+
+			var f : text;
+
+			begin
+				seek(f, 1)
+			end.`,
+		},
+		{
+			"enum subrange variable assigned value outside subrange",
+			"assignment: blue is outside subrange type red..green",
+			`program test;
+
+			type primary = (red, green, blue);
+				warm = red..green;
+
+			var c : warm;
+
+			begin
+				c := red;
+				c := blue
+			end.`,
+		},
+		{
+			"integer subrange variable assigned value outside subrange",
+			"assignment: 11 is outside subrange type 1..10",
+			`program test;
+
+			var i : 1..10;
+
+			begin
+				i := 11
+			end.`,
+		},
+		{
+			"integer subrange variable assigned negative value outside subrange",
+			"assignment: -5 is outside subrange type 1..10",
+			`program test;
+
+			var i : 1..10;
+
+			begin
+				i := -5
+			end.`,
+		},
+		{
+			"integer subrange variable assigned constant outside subrange",
+			"assignment: 11 is outside subrange type 1..10",
+			`program test;
+
+			const k = 11;
+
+			var i : 1..10;
+
+			begin
+				i := k
+			end.`,
+		},
+		{
+			"integer subrange variable assigned constant expression outside subrange",
+			"assignment: 12 is outside subrange type 1..10",
+			`program test;
+
+			const k = 4;
+
+			var i : 1..10;
+
+			begin
+				i := k * 3
+			end.`,
+		},
+		{
+			"get on non-file",
+			"get: argument has to be a file variable, got integer instead",
+			`program test;
+
+			var i : integer;
+
+			begin
+				get(i)
+			end.`,
+		},
+		{
+			"new with too many arguments",
+			"new requires exactly 1 argument of a pointer type, got 2 arguments instead",
+			`program test;
+
+			var p : ^integer;
+
+			begin
+				new(p, 1)
+			end.`,
+		},
+		{
+			"page on typed file",
+			"page: argument has to be a text file variable, got file of integer instead",
+			`program test;
+
+			var f : file of integer;
+
+			begin
+				page(f)
+			end.`,
+		},
+		{
+			"eoln on typed file",
+			"eoln requires exactly 1 argument of text file type, got file of integer instead",
+			`program test;
+
+			var f : file of integer;
+				b : boolean;
+
+			begin
+				b := eoln(f)
+			end.`,
+		},
+		{
+			"page with too many arguments",
+			"page: need at most 1 argument of text file type, got 2 arguments instead",
+			`program test;
+
+			var f, g : text;
+
+			begin
+				page(f, g)
+			end.`,
+		},
+		{
+			"write of wrong type to typed file",
+			"can't write real to file of integer",
+			`program test;
+
+			var f : file of integer;
+
+			begin
+				rewrite(f);
+				write(f, 3.5)
+			end.`,
+		},
+		{
+			"comparison of pointers to different types",
+			"types ^integer and ^real are incompatible",
+			`program test;
+
+			var p : ^integer;
+				q : ^real;
+
+			begin
+				if p = q then
+					writeln('equal')
+			end.`,
+		},
+		{
+			"assignment of integer set to boolean set",
+			"incompatible types: got set of integer, expected set of boolean",
+			`program test;
+
+			var b : set of boolean;
+				i : set of integer;
+
+			begin
+				i := [0, 1];
+				b := i
+			end.`,
+		},
+		{
+			"case statement over enum subrange with out-of-range label",
+			"case label yellow doesn't match case expression type red..blue",
+			`program test;
+
+			type colour = (red, green, blue, yellow);
+				primary = red..blue;
+
+			var p : primary;
+
+			begin
+				case p of
+					red: writeln('red');
+					yellow: writeln('yellow')
+				end
+			end.`,
+		},
+		{
+			"case statement over enum subrange with label of different enum",
+			"case label circle doesn't match case expression type red..blue",
+			`program test;
+
+			type colour = (red, green, blue, yellow);
+				shape = (circle, square);
+				primary = red..blue;
+
+			var p : primary;
+
+			begin
+				case p of
+					circle: writeln('circle')
+				end
+			end.`,
+		},
+		{
+			"exit with arguments",
+			"exit: no arguments allowed, got 1 arguments instead",
+			`program test;
+
+			begin
+				exit(1)
+			end.`,
+		},
+		{
+			"string with maximum length assigned to char array of different length",
+			`incompatible types: got string[5], expected packed array [1..10] of char`,
+			`program test;
+
+			var s : string[5];
+				a : packed array[1..10] of char;
+
+			begin
+				a := s
+			end.`,
+		},
+		{
+			"char array assigned to string with different maximum length",
+			`incompatible types: got packed array [1..10] of char, expected string[5]`,
+			`program test;
+
+			var s : string[5];
+				a : packed array[1..10] of char;
+
+			begin
+				s := a
+			end.`,
+		},
+		{
+			"string with non-positive maximum length",
+			`string length must be a positive integer`,
+			`program test;
+
+			var s : string[0];
+
+			begin
+			end.`,
+		},
+		{
+			"symmetric difference of integers",
+			`can't use >< operator with integer`,
+			`program test;
+
+			var a : integer;
+
+			begin
+				a := 1 >< 2
+			end.`,
+		},
+		{
+			"abs of char subrange",
+			`abs requires exactly 1 argument of type integer or real, got 'a'..'z' instead`,
+			`program test;
+
+			var c : 'a'..'z';
+
+			begin
+				writeln(abs(c))
+			end.`,
+		},
+		{
+			"missing semicolon between variable declarations",
+			`expected ;, got identifier "b"`,
+			`program test;
+
+			var a : integer
+				b : integer;
+
+			begin
+			end.`,
+		},
+		{
+			"unexpected end of file after program end",
+			`expected ., got end-of-file instead`,
+			`program test;
+
+			begin
+				writeln(1)
+			end`,
+		},
+		{
+			"constant arithmetic with string",
+			`can't use + operator in constant with string`,
+			`program test;
+
+			const r = 'foo' + 'bar';
+
+			begin
+			end.`,
+		},
+		{
+			"boolean succ in arithmetic",
+			"can't use + operator with boolean",
+			`program test;
+
+			var b : boolean;
+				n : integer;
+
+			begin
+				n := succ(b) + 1
+			end.`,
+		},
+		{
+			"boolean pred assigned to integer",
+			"incompatible types: got boolean, expected integer",
+			`program test;
+
+			var b : boolean;
+				n : integer;
+
+			begin
+				n := pred(b)
+			end.`,
+		},
+		{
+			"ord of boolean assigned to boolean",
+			"incompatible types: got integer, expected boolean",
+			`program test;
+
+			var b : boolean;
+
+			begin
+				b := ord(b)
+			end.`,
+		},
+		{
+			"ord of boolean in boolean expression",
+			"can't use and with integer",
+			`program test;
+
+			var b : boolean;
+
+			begin
+				b := ord(b) and b
+			end.`,
+		},
+		{
+			"comparison of enum subrange variable with constant of different enum",
+			"in relational expression with operator =, types red..blue and shape are incompatible",
+			`program test;
+
+			type colour = (red, green, blue, yellow);
+				shape = (circle, square);
+				primary = red..blue;
+
+			var c : primary;
+
+			begin
+				if c = circle then
+					writeln('circle')
+			end.`,
+		},
+		{
+			"duplicate variant case label",
+			"duplicate variant case label 1",
+			`program test;
+
+			type foo = record
+					case tag : integer of
+						1: ( a : integer );
+						2, 1: ( b : real );
+				end;
+
+			begin
+			end.`,
+		},
+		{
+			"in with subrange operand and incompatible set",
+			"type 1..100 does not match set type char",
+			`program test;
+
+			var n : 1..100;
+
+			begin
+				if n in ['a', 'b'] then
+					writeln('yes')
+			end.`,
+		},
+		{
+			"assignment to constant",
+			"cannot assign to constant pi",
+			`program test;
+
+			const pi = 3.14;
+
+			begin
+				pi := 4
+			end.`,
+		},
+		{
+			"assignment to function call",
+			"cannot assign to result of function call f",
+			`program test;
+
+			var x : integer;
+
+			function f(a : integer) : integer;
+			begin
+				f := a
+			end;
+
+			begin
+				f(x) := 1
+			end.`,
+		},
+		{
+			"assignment to literal",
+			"cannot assign to 3, as it is not a variable",
+			`program test;
+
+			var x : integer;
+
+			begin
+				3 := x
+			end.`,
+		},
+		{
+			"with over function result",
+			"result of function call getrec is not a record variable",
+			`program test;
+
+			type rec = record
+					a : integer
+				end;
+
+			var r : rec;
+
+			function getrec(n : integer) : rec;
+			begin
+				getrec := r
+			end;
+
+			begin
+				with getrec(1) do
+					a := 1
+			end.`,
+		},
+		{
+			"length of integer",
+			"length requires exactly 1 argument of type string, got integer instead",
+			`program test;
+
+			var n : integer;
+
+			begin
+				n := length(n)
+			end.`,
+		},
+	}
+
+	for idx, tt := range testData {
+		t.Run(tt.Name, func(t *testing.T) {
+			p, err := Parse(fmt.Sprintf("test_%d.pas", idx), tt.Code)
+			if err == nil {
+				t.Errorf("Parsing code unexpectedly didn't return error")
+			} else if !strings.Contains(err.Error(), tt.ExpectedError) {
+				t.Logf("expected error = %s", tt.ExpectedError)
+				t.Errorf("Parsing returned error, but didn't contain expected error message")
+			}
+			_ = p
+			t.Logf("error = %v", err)
+		})
+	}
+}
+
+func TestParserInlinePointerTypes(t *testing.T) {
+	code := `program test;
+
+	type ptr = ^record value : integer end;
+
+	var p : ^record a : integer end;
+		q : ptr;
+
+	begin
+		new(p);
+		p^.a := 23;
+		new(q);
+		q^.value := p^.a
+	end.`
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err, "parsing pointer to inline record type unexpectedly succeeded without option")
+
+	ast, err := Parse("test.pas", code, WithInlinePointerTypes())
+	require.NoError(t, err)
+
+	pt, ok := ast.Block.findVariable("p").Type.(*PointerType)
+	require.True(t, ok, "p is not a pointer type")
+	require.Equal(t, "^anon_type_2", pt.TypeString())
+
+	_, ok = pt.Type_.(*RecordType)
+	require.True(t, ok, "p doesn't point to a record type")
+	require.NotNil(t, ast.Block.findType("anon_type_2"))
+}
+
+func TestParserEnumInSet(t *testing.T) {
+	code := `program test;
+
+	type colour = (red, green, blue);
+
+	var c : colour;
+		b : boolean;
+
+	begin
+		c := green;
+		b := c in [red, green]
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+
+	stmt, ok := ast.Block.Statements[1].(*AssignmentStatement)
+	require.True(t, ok, "second statement is not an assignment")
+
+	relExpr, ok := stmt.RightExpr.(*RelationalExpr)
+	require.True(t, ok, "right side of assignment is not a relational expression")
+	require.Equal(t, OpIn, relExpr.Operator)
+
+	colourType := ast.Block.findType("colour")
+	require.NotNil(t, colourType)
+	require.True(t, colourType.Equals(relExpr.Left.Type()), "left operand is not of type colour")
+
+	setType, ok := relExpr.Right.Type().(*SetType)
+	require.True(t, ok, "right operand is not a set")
+	require.True(t, colourType.Equals(setType.ElementType), "set element type is not colour")
+}
+
+func TestParserLabeledCompoundStatement(t *testing.T) {
+	code := `program test;
+
+	label 10;
+
+	var i : integer;
+
+	begin
+		i := 0;
+	10: begin
+			i := i + 1;
+			writeln(i)
+		end;
+		if i < 3 then
+			goto 10
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+	require.Len(t, ast.Block.Statements, 3)
+
+	stmt, ok := ast.Block.Statements[1].(*CompoundStatement)
+	require.True(t, ok, "labeled statement is not a compound statement")
+	require.NotNil(t, stmt.Label())
+	require.Equal(t, "10", *stmt.Label())
+	require.Len(t, stmt.Statements, 2)
+}
+
+func TestParserNotIn(t *testing.T) {
+	code := `program test;
+
+	var c : char;
+		vowels : set of char;
+
+	begin
+		vowels := ['a', 'e', 'i', 'o', 'u'];
+		c := 'x';
+		if not (c in vowels) then
+			writeln('not a vowel')
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+	require.Len(t, ast.Block.Statements, 3)
+
+	stmt, ok := ast.Block.Statements[2].(*IfStatement)
+	require.True(t, ok, "third statement is not an if statement")
+	require.True(t, IsBooleanType(stmt.Condition.Type()), "condition is not boolean")
+
+	notExpr, ok := stmt.Condition.(*NotExpr)
+	require.True(t, ok, "condition is not a not expression")
+	subExpr, ok := notExpr.Expr.(*SubExpr)
+	require.True(t, ok, "negated expression is not a sub-expression")
+	relExpr, ok := subExpr.Expr.(*RelationalExpr)
+	require.True(t, ok, "sub-expression is not a relational expression")
+	require.Equal(t, OpIn, relExpr.Operator)
+}
+
+func TestParserRepeatEmptyBody(t *testing.T) {
+	code := `program test;
+
+	var done : boolean;
+
+	begin
+		done := true;
+		repeat until done
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+	require.Len(t, ast.Block.Statements, 2)
+
+	stmt, ok := ast.Block.Statements[1].(*RepeatStatement)
+	require.True(t, ok, "second statement is not a repeat statement")
+	require.Empty(t, stmt.Statements)
+	require.Equal(t, &VariableExpr{Name: "done", Type_: booleanTypeDef.Type, VarDecl: ast.Block.findVariable("done")}, stmt.Condition)
+}
+
+func TestParserBooleanSet(t *testing.T) {
+	code := `program test;
+
+	var flags : set of boolean;
+		b : boolean;
+
+	begin
+		flags := [true, false];
+		b := true in flags
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+
+	assignment, ok := ast.Block.Statements[0].(*AssignmentStatement)
+	require.True(t, ok, "first statement is not an assignment")
+	setType, ok := assignment.RightExpr.Type().(*SetType)
+	require.True(t, ok, "set literal is not of a set type")
+	require.True(t, IsBooleanType(setType.ElementType), "set literal element type is not boolean")
+
+	assignment, ok = ast.Block.Statements[1].(*AssignmentStatement)
+	require.True(t, ok, "second statement is not an assignment")
+	relExpr, ok := assignment.RightExpr.(*RelationalExpr)
+	require.True(t, ok, "right side of assignment is not a relational expression")
+	require.Equal(t, OpIn, relExpr.Operator)
+	require.True(t, IsBooleanType(relExpr.Type()))
+}
+
+func TestParserLineComments(t *testing.T) {
+	code := `program test; // line comment
+	var x : integer;
+	begin
+		x := 1 // another one
+	end.`
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err, "parsing line comments unexpectedly succeeded without option")
+
+	_, err = Parse("test.pas", code, WithLineComments())
+	require.NoError(t, err)
+}
+
+func TestParserErrorSnippets(t *testing.T) {
+	code := "program test;\n\nvar a : integer\n\tb : integer;\n\nbegin\nend."
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err)
+	require.Equal(t, `test.pas:4:3: expected ;, got identifier "b"`, err.Error())
+
+	_, err = Parse("test.pas", code, WithErrorSnippets())
+	require.Error(t, err)
+	require.Equal(t, "test.pas:4:3: expected ;, got identifier \"b\"\n\tb : integer;\n\t^", err.Error())
+}
+
+func TestParserParseError(t *testing.T) {
+	code := "program test;\n\nbegin\n\twriteln(1)\nend"
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err)
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr), "error is not a *ParseError")
+	require.Equal(t, "test.pas", parseErr.File)
+	require.Equal(t, 5, parseErr.Line)
+	require.Equal(t, 5, parseErr.Column)
+	require.Equal(t, "expected ., got end-of-file instead", parseErr.Msg)
+	require.Empty(t, parseErr.Snippet)
+	require.Equal(t, "test.pas:5:5: expected ., got end-of-file instead", err.Error())
+}
+
+func TestParserStringLength(t *testing.T) {
+	code := `program test;
+
+	const n = 5;
+
+	var s : string[10];
+		t : string[n];
+		u : string;
+		a : packed array[1..10] of char;
+		b : packed array[1..5] of char;
+
+	begin
+		s := a;
+		a := s;
+		t := b;
+		b := t;
+		u := t;
+		t := u
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+
+	require.Equal(t, &StringType{MaxLength: 10}, ast.Block.findVariable("s").Type)
+	require.Equal(t, &StringType{MaxLength: 5}, ast.Block.findVariable("t").Type)
+	require.Equal(t, &StringType{}, ast.Block.findVariable("u").Type)
+	require.Equal(t, "string[10]", ast.Block.findVariable("s").Type.TypeString())
+}
+
+func TestParserExplicitEnumValues(t *testing.T) {
+	code := `program test;
+
+	type x = (a = 1, b = 2, c = 5, d);
+
+	var i : integer;
+
+	begin
+		i := ord(c)
+	end.`
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err, "parsing explicit enum values unexpectedly succeeded without option")
+
+	ast, err := Parse("test.pas", code, WithExplicitEnumValues())
+	require.NoError(t, err)
+
+	enumType, ok := ast.Block.findType("x").(*EnumType)
+	require.True(t, ok, "x is not an enum type")
+	require.Equal(t, []string{"a", "b", "c", "d"}, enumType.Identifiers)
+	require.Equal(t, []int{1, 2, 5, 6}, enumType.Values)
+	require.Equal(t, 6, enumType.MaxValue())
+
+	assignment, ok := ast.Block.Statements[0].(*AssignmentStatement)
+	require.True(t, ok, "first statement is not an assignment")
+	ordCall, ok := assignment.RightExpr.(*FunctionCallExpr)
+	require.True(t, ok, "right side of assignment is not a function call")
+	require.Equal(t, &EnumValueExpr{Name: "c", Value: 5, Type_: ast.Block.findType("x")}, ordCall.ActualParams[0])
+
+	_, err = Parse("test.pas", `program test;
+
+	type x = (a = 3, b = 2);
+
+	begin
+	end.`, WithExplicitEnumValues())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "value of enum identifier b must be greater than 3")
+
+	ast, err = Parse("test.pas", `program test;
+
+	type x = (a, b, c);
+
+	begin
+	end.`, WithExplicitEnumValues())
+	require.NoError(t, err)
+	require.Nil(t, ast.Block.findType("x").(*EnumType).Values)
+}
+
+func TestParserSymmetricDifference(t *testing.T) {
+	code := `program test;
+
+	var a, b, c : set of 1..10;
+
+	begin
+		c := a >< b + [5]
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+
+	assignment, ok := ast.Block.Statements[0].(*AssignmentStatement)
+	require.True(t, ok, "first statement is not an assignment")
+	simpleExpr, ok := assignment.RightExpr.(*SimpleExpr)
+	require.True(t, ok, "right side of assignment is not a simple expression")
+	require.Len(t, simpleExpr.Next, 2)
+	require.Equal(t, OperatorSymmetricDifference, simpleExpr.Next[0].Operator)
+	require.Equal(t, OperatorAdd, simpleExpr.Next[1].Operator)
+	_, isSetType := simpleExpr.Type().(*SetType)
+	require.True(t, isSetType, "simple expression is not of a set type")
+}
+
+func TestParserTextType(t *testing.T) {
+	code := `program test;
+
+	var f : text;
+		line : string;
+
+	begin
+		reset(f);
+		readln(f, line)
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+
+	varDecl := ast.Block.findVariable("f")
+	require.NotNil(t, varDecl)
+	require.True(t, IsTextType(varDecl.Type), "f is not of type text")
+	require.True(t, getBuiltinType("text").Equals(varDecl.Type))
+	require.Equal(t, "text", varDecl.Type.TypeName())
+}
+
+func TestConstantFolding(t *testing.T) {
+	testData := []struct {
+		Name          string
+		Code          string
+		ExpectedValue int
+	}{
+		{"mod of negative constant", "program test; const r = -7 mod 3; begin end.", -1},
+		{"mod of positive constant", "program test; const r = 7 mod 3; begin end.", 1},
+		{"div of negative constant", "program test; const r = -7 div 2; begin end.", -3},
+		{"operator precedence", "program test; const r = 2 + 3 * 4 - 10 div 5; begin end.", 12},
+		{"previously defined constant", "program test; const a = 10; r = a * a + maxint mod 2; begin end.", 101},
+	}
+
+	for idx, tt := range testData {
+		t.Run(tt.Name, func(t *testing.T) {
+			ast, err := Parse(fmt.Sprintf("test_%d.pas", idx), tt.Code)
+			require.NoError(t, err)
+
+			constDecl := ast.Block.findConstantDeclaration("r")
+			require.NotNil(t, constDecl)
+			require.Equal(t, &IntegerLiteral{Value: tt.ExpectedValue}, constDecl.Value)
+		})
+	}
+}
+
+func TestRealConstantNegation(t *testing.T) {
+	code := `program test;
+
+	const a = 1.5;
+		b = -a;
+		c = -b;
+
+	var r : real;
+
+	begin
+		r := 1.0;
+		if c > r then
+			writeln('c is positive')
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+
+	expected := map[string]*RealLiteral{
+		"a": {Minus: false, BeforeComma: "1", AfterComma: "5"},
+		"b": {Minus: true, BeforeComma: "1", AfterComma: "5"},
+		"c": {Minus: false, BeforeComma: "1", AfterComma: "5"},
+	}
+
+	for name, expectedValue := range expected {
+		constDecl := ast.Block.findConstantDeclaration(name)
+		require.NotNil(t, constDecl, "constant %s not found", name)
+		require.Equal(t, expectedValue, constDecl.Value, "constant %s has unexpected value", name)
+	}
+}
+
+func TestParserOnTranspileSet(t *testing.T) {
+	pascalFiles, err := filepath.Glob("../pas2go/testdata/*.pas")
+	require.NoError(t, err)
+
+	for _, pascalFile := range pascalFiles {
+		t.Run(filepath.Base(pascalFile), func(t *testing.T) {
+			fileContent, err := ioutil.ReadFile(pascalFile)
+			require.NoError(t, err)
+
+			ast, err := Parse(pascalFile, string(fileContent))
+			require.NoError(t, err, "parsing source file failed")
+			require.NotNil(t, ast, "parsing source succeeded, but ast is nil")
+		})
+	}
+}
+
+func TestParserTypedConstants(t *testing.T) {
+	code := `program test;
+
+	const m : array[1..2, 1..2] of integer = ((1, 2), (3, 4));
+		limit : integer = 10;
+
+	begin
+		limit := m[2, 1]
+	end.`
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err, "parsing typed constants unexpectedly succeeded without option")
+
+	ast, err := Parse("test.pas", code, WithTypedConstants())
+	require.NoError(t, err)
+
+	m := ast.Block.findVariable("m")
+	require.NotNil(t, m)
+	require.Equal(t, "((1, 2), (3, 4))", m.InitialValue.String())
+
+	lit, ok := m.InitialValue.(*ArrayLiteral)
+	require.True(t, ok, "initial value of m is not an array literal")
+	require.Len(t, lit.Elements, 2)
+	require.Equal(t, "array [1..2] of integer", lit.Elements[0].ConstantType().TypeString())
+
+	limit := ast.Block.findVariable("limit")
+	require.NotNil(t, limit)
+	require.Equal(t, &IntegerLiteral{Value: 10}, limit.InitialValue)
+
+	for _, tc := range []struct {
+		code string
+		err  string
+	}{
+		{"const m : array[1..2, 1..2] of integer = ((1, 2), (3));", "expected 2 values for array [1..2] of integer, got 1"},
+		{"const m : array[1..2] of integer = (1, 2, 3);", "expected 2 values for array [1..2] of integer, got 3"},
+		{"const m : array[1..2] of integer = 1;", `expected (, got number "1"`},
+		{"const c : char = 3;", "constant of type integer is not compatible with type char"},
+	} {
+		_, err := Parse("test.pas", "program test;\n"+tc.code+"\nbegin\nend.", WithTypedConstants())
+		require.Error(t, err, tc.code)
+		require.Contains(t, err.Error(), tc.err, tc.code)
+	}
+}
+
+func TestParserFunctionCallStatements(t *testing.T) {
+	code := `program test;
+
+	var x : integer;
+
+	function compute(i : integer) : integer;
+	begin
+		compute := i * 2
+	end;
+
+	function answer : integer;
+	begin
+		answer := 42
+	end;
+
+	begin
+		x := 3;
+		compute(x);
+		answer
+	end.`
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err, "parsing function call statement unexpectedly succeeded without option")
+	require.Contains(t, err.Error(), "unknown procedure compute")
+
+	ast, err := Parse("test.pas", code, WithFunctionCallStatements())
+	require.NoError(t, err)
+	require.Len(t, ast.Block.Statements, 3)
+
+	stmt, ok := ast.Block.Statements[1].(*ProcedureCallStatement)
+	require.True(t, ok, "second statement is not a procedure call statement")
+	require.Equal(t, "compute", stmt.Name)
+	require.Len(t, stmt.ActualParams, 1)
+
+	stmt, ok = ast.Block.Statements[2].(*ProcedureCallStatement)
+	require.True(t, ok, "third statement is not a procedure call statement")
+	require.Equal(t, "answer", stmt.Name)
+
+	_, err = Parse("test.pas", `program test;
+	begin
+		odd(3)
+	end.`, WithFunctionCallStatements())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown procedure odd")
+}
+
+func TestParserControlCharacters(t *testing.T) {
+	code := `program test;
+
+	const cr = ^M;
+
+	type m = integer;
+		pm = ^M;
+
+	var ch : char;
+		p : pm;
+
+	begin
+		ch := ^M;
+		ch := ^j;
+		new(p);
+		p^ := 3;
+		case ch of
+			^I, cr: ch := ^A
+		end
+	end.`
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err, "parsing control characters unexpectedly succeeded without option")
+
+	ast, err := Parse("test.pas", code, WithControlCharacters())
+	require.NoError(t, err)
+
+	require.Equal(t, &CharLiteral{Value: 13}, ast.Block.findConstantDeclaration("cr").Value)
+
+	pt, ok := ast.Block.findType("pm").(*PointerType)
+	require.True(t, ok, "pm is not a pointer type")
+	require.Equal(t, "m", pt.TargetName)
+
+	assignment, ok := ast.Block.Statements[0].(*AssignmentStatement)
+	require.True(t, ok, "first statement is not an assignment")
+	require.Equal(t, &CharExpr{Value: '\r'}, assignment.RightExpr)
+
+	assignment, ok = ast.Block.Statements[1].(*AssignmentStatement)
+	require.True(t, ok, "second statement is not an assignment")
+	require.Equal(t, &CharExpr{Value: '\n'}, assignment.RightExpr)
+}
+
+func TestParserSqrtOfInteger(t *testing.T) {
+	code := `program test;
+
+	var r : real;
+
+	begin
+		r := sqrt(4)
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+
+	assignment, ok := ast.Block.Statements[0].(*AssignmentStatement)
+	require.True(t, ok, "first statement is not an assignment")
+
+	call, ok := assignment.RightExpr.(*FunctionCallExpr)
+	require.True(t, ok, "right side of assignment is not a function call")
+	require.Equal(t, "sqrt", call.Name)
+	require.Equal(t, &RealType{}, call.Type())
+	require.Equal(t, &IntegerExpr{Value: 4}, call.ActualParams[0])
+	require.Len(t, call.FormalParams, 1, "formal parameters got lost")
+}
+
+func TestParserStraySemicolons(t *testing.T) {
+	code := `program test;
+
+	var i : integer;
+
+	procedure p;
+	var j : integer;
+	;
+	begin
+		j := 1
+	end;
+	;;
+	begin
+		i := 1;
+		p
+	end.`
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err, "parsing stray semicolons unexpectedly succeeded without option")
+	require.Contains(t, err.Error(), `expected begin, got ";" instead`)
+
+	ast, err := Parse("test.pas", code, WithStraySemicolons())
+	require.NoError(t, err)
+	require.Len(t, ast.Block.Statements, 2)
+	require.Len(t, ast.Block.Procedures, 1)
+}
+
+func TestParserWritableSets(t *testing.T) {
+	code := `program test;
+
+	var s : set of char;
+
+	begin
+		s := ['a', 'b'];
+		writeln('s = ', s)
+	end.`
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err, "parsing writeln of set unexpectedly succeeded without option")
+	require.Contains(t, err.Error(), "can't use variables of type set of char with writeln")
+
+	_, err = Parse("test.pas", code, WithWritableSets())
+	require.NoError(t, err)
+}
+
+func TestParserBracketDigraphs(t *testing.T) {
+	for _, index := range []string{"a(.i.)", "a[i]", "a(.i]", "a[i.)"} {
+		t.Run(index, func(t *testing.T) {
+			code := `program test;
+
+			var a : array(.1..3.) of integer;
+				i, j : integer;
+
+			begin
+				i := 2;
+				j := ` + index + `
+			end.`
+
+			ast, err := Parse("test.pas", code)
+			require.NoError(t, err)
+
+			assignment, ok := ast.Block.Statements[1].(*AssignmentStatement)
+			require.True(t, ok, "second statement is not an assignment")
+
+			indexExpr, ok := assignment.RightExpr.(*IndexedVariableExpr)
+			require.True(t, ok, "right side of assignment is not an indexed variable")
+			require.Len(t, indexExpr.IndexExprs, 1)
+		})
+	}
+}
+
+func TestParserMaxintArrayBounds(t *testing.T) {
+	code := `program test;
+
+	const size = maxint div 1000000000000;
+
+	var a : array[1..size] of integer;
+		b : array[0..maxint div 1000000000000 - 1] of char;
+
+	begin
+		a[size] := 1;
+		b[0] := 'x'
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+
+	expectedSize := math.MaxInt / 1000000000000
+
+	require.Equal(t, &IntegerLiteral{Value: expectedSize}, ast.Block.Constants[0].Value)
+
+	aType, ok := ast.Block.Variables[0].Type.(*ArrayType)
+	require.True(t, ok, "a is not an array")
+	require.Equal(t, expectedSize, aType.IndexTypes[0].(*SubrangeType).UpperBound)
+
+	bType, ok := ast.Block.Variables[1].Type.(*ArrayType)
+	require.True(t, ok, "b is not an array")
+	require.Equal(t, expectedSize-1, bType.IndexTypes[0].(*SubrangeType).UpperBound)
+}
+
+func TestParserTypeCasts(t *testing.T) {
+	code := `program test;
+
+	type colour = (red, green, blue);
+
+	var ch : char;
+		n : integer;
+		c : colour;
+
+	begin
+		ch := 'A';
+		n := integer(ch);
+		ch := char(n + 1);
+		c := colour(n - 64)
+	end.`
+
+	_, err := Parse("test.pas", code)
+	require.Error(t, err)
+
+	ast, err := Parse("test.pas", code, WithTypeCasts())
+	require.NoError(t, err)
+
+	stmt := ast.Block.Statements[1].(*AssignmentStatement)
+	cast, ok := stmt.RightExpr.(*TypeCastExpr)
+	require.True(t, ok, "integer(ch) is not a type cast")
+	require.True(t, cast.Type().Equals(&IntegerType{}))
+	require.True(t, IsCharType(cast.Expr.Type()))
+
+	stmt = ast.Block.Statements[2].(*AssignmentStatement)
+	cast, ok = stmt.RightExpr.(*TypeCastExpr)
+	require.True(t, ok, "char(n + 1) is not a type cast")
+	require.True(t, IsCharType(cast.Type()))
+
+	_, err = Parse("test.pas", `program test;
+	var r : real;
+		n : integer;
+	begin
+		n := integer(r)
+	end.`, WithTypeCasts())
+	require.EqualError(t, err, "test.pas:5:18: can't convert real to integer, as it is not an ordinal type")
+
+	_, err = Parse("test.pas", `program test;
+	var r : real;
+	begin
+		r := real(1)
+	end.`, WithTypeCasts())
+	require.EqualError(t, err, "test.pas:4:13: can't convert to real, as it is not an ordinal type")
+}
+
+func TestParserUsedBuiltins(t *testing.T) {
+	code := `program test;
+
+	var r : real;
+
+	function sqr(x : real) : real;
+	begin
+		sqr := x * x
+	end;
+
+	begin
+		r := sqrt(sqr(2.0));
+		writeln(r);
+		writeln
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sqrt", "writeln"}, ast.UsedBuiltins)
+
+	ast, err = Parse("test.pas", `program test;
+	var i : integer;
+	begin
+		i := 1
+	end.`)
+	require.NoError(t, err)
+	require.Empty(t, ast.UsedBuiltins)
+}
+
+func TestParserNestedForwardDeclaration(t *testing.T) {
+	code := `program test;
+
+	var n : integer;
+
+	procedure outer;
+
+		procedure b(x : integer); forward;
+
+		procedure a(x : integer);
+		begin
+			if x > 0 then
+				b(x - 1)
+		end;
+
+		procedure b;
+		begin
+			n := n + 1;
+			a(x)
+		end;
+
+	begin
+		a(5)
+	end;
+
+	begin
+		n := 0;
+		outer
+	end.`
+
+	ast, err := Parse("test.pas", code)
+	require.NoError(t, err)
+
+	require.Len(t, ast.Block.Procedures, 1)
+	outer := ast.Block.Procedures[0]
+	require.Len(t, outer.Block.Procedures, 2)
+
+	b := outer.Block.findProcedure("b")
+	require.NotNil(t, b)
+	require.False(t, b.Forward, "b is still only forward-declared")
+	require.NotNil(t, b.Block)
+	require.Len(t, b.FormalParameters, 1)
+	require.Equal(t, "x", b.FormalParameters[0].Name)
+
+	require.Nil(t, ast.Block.findProcedure("b"), "b is visible outside of outer")
+}
+
+func TestParserStrictISO(t *testing.T) {
+	testData := []struct {
+		name          string
+		code          string
+		expectedError string
+	}{
+		{
+			"string type",
+			`program test;
+			var s : string;
+			begin
+				s := 'hello'
+			end.`,
+			"type string is not part of ISO Pascal",
+		},
+		{
+			"inc",
+			`program test;
+			var i : integer;
+			begin
+				i := 0;
+				inc(i)
+			end.`,
+			"inc is not part of ISO Pascal",
+		},
+		{
+			"exit",
+			`program test;
+			procedure p;
+			begin
+				exit
+			end;
+			begin
+				p
+			end.`,
+			"exit is not part of ISO Pascal",
+		},
+		{
+			"frac",
+			`program test;
+			var r : real;
+			begin
+				r := frac(1.5)
+			end.`,
+			"frac is not part of ISO Pascal",
+		},
+		{
+			"writeln without output",
+			`program test;
+			begin
+				writeln('hello')
+			end.`,
+			"writeln to standard output requires output in the program heading",
+		},
+		{
+			"bare writeln without output",
+			`program test(input);
+			begin
+				writeln
+			end.`,
+			"writeln to standard output requires output in the program heading",
+		},
+		{
+			"write without output",
+			`program test;
+			var i : integer;
+			begin
+				i := 1;
+				write(i)
+			end.`,
+			"write to standard output requires output in the program heading",
+		},
+		{
+			"symmetric difference",
+			`program test;
+			var s : set of 1..9;
+			begin
+				s := [1, 2] >< [2, 3]
+			end.`,
+			"operator is not part of ISO Pascal",
+		},
+		{
+			"constant expression in constant definition",
+			`program test;
+			const a = 1;
+				b = a + 1;
+			begin
+			end.`,
+			"constant expressions are not part of ISO Pascal",
+		},
+		{
+			"constant expression in subrange bound",
+			`program test;
+			const n = 5;
+			type r = 1..n * 2;
+			begin
+			end.`,
+			"constant expressions are not part of ISO Pascal",
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("test.pas", tt.code)
+			require.NoError(t, err)
+
+			_, err = Parse("test.pas", tt.code, WithStrictISO())
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+
+	_, err := Parse("test.pas", `program test(output);
+	var p : ^integer;
+		i : integer;
+	begin
+		new(p);
+		p@ := 3;
+		for i := 1 to p^ do
+			writeln(sqr(i), ' ', trunc(sqrt(i)));
+		dispose(p)
+	end.`, WithStrictISO())
+	require.NoError(t, err)
+
+	_, err = Parse("test.pas", `program test(input, output);
+	var f : text;
+	begin
+		rewrite(f);
+		writeln(f, 'to file');
+		write('hello');
+		writeln
+	end.`, WithStrictISO())
+	require.NoError(t, err)
+
+	_, err = Parse("test.pas", `program test;
+	var f : text;
+	begin
+		rewrite(f);
+		writeln(f, 'to file')
+	end.`, WithStrictISO())
+	require.NoError(t, err)
+
+	_, err = Parse("test.pas", `program test;
+	const n = 5;
+		m = -n;
+	type r = -5..n;
+	var s : set of r;
+	begin
+		s := [1, 2] + [m]
+	end.`, WithStrictISO())
+	require.NoError(t, err)
+}
+
+func TestParserNestedPackedArrays(t *testing.T) {
+	ast, err := Parse("test.pas", `program test;
+
+	type row = array[1..8] of boolean;
+
+	var a : packed array[1..10] of packed array[1..8] of boolean;
+		b : packed array[1..10] of array[1..8] of boolean;
+		c : packed array[1..10] of row;
+
+	begin
+		a[1, 2] := true;
+		a[1][2] := b[1, 2];
+		b[3][4] := c[5, 6]
+	end.`)
+	require.NoError(t, err)
+
+	a := ast.Block.findVariable("a").Type.(*ArrayType)
+	require.True(t, a.Packed)
+	require.Len(t, a.IndexTypes, 2)
+	require.True(t, IsBooleanType(a.ElementType))
+
+	b := ast.Block.findVariable("b").Type.(*ArrayType)
+	require.True(t, b.Packed)
+	require.Len(t, b.IndexTypes, 1)
+	inner, ok := b.ElementType.(*ArrayType)
+	require.True(t, ok, "element type of b is not an array")
+	require.False(t, inner.Packed)
+	require.Len(t, inner.IndexTypes, 1)
+
+	c := ast.Block.findVariable("c").Type.(*ArrayType)
+	require.True(t, c.Packed)
+	require.Len(t, c.IndexTypes, 1)
+	require.False(t, c.ElementType.(*ArrayType).Packed)
+
+	stmt := ast.Block.Statements[1].(*AssignmentStatement)
+	indexedExpr := stmt.RightExpr.(*IndexedVariableExpr)
+	require.Len(t, indexedExpr.IndexExprs, 1)
+	require.True(t, IsBooleanType(indexedExpr.Type()))
+	require.Len(t, indexedExpr.Expr.(*IndexedVariableExpr).IndexExprs, 1)
+}
+
+func TestParserDanglingElse(t *testing.T) {
+	ast, err := Parse("test.pas", `program test;
+
+	var a, b : boolean;
+		x : integer;
+
+	begin
+		if a then if b then x := 1 else x := 2
+	end.`)
+	require.NoError(t, err)
+
+	require.Len(t, ast.Block.Statements, 1)
+	outer, ok := ast.Block.Statements[0].(*IfStatement)
+	require.True(t, ok, "statement is not an if statement")
+	require.Nil(t, outer.ElseStatement, "else is attached to the outer if")
+
+	inner, ok := outer.Statement.(*IfStatement)
+	require.True(t, ok, "then statement is not an if statement")
+	require.NotNil(t, inner.ElseStatement, "else is not attached to the inner if")
+
+	elseStmt, ok := inner.ElseStatement.(*AssignmentStatement)
+	require.True(t, ok, "else statement is not an assignment")
+	require.Equal(t, 2, elseStmt.RightExpr.(*IntegerExpr).Value)
+}
+
+func TestParserNestingDepth(t *testing.T) {
+	nestedExpr := func(depth int) string {
+		return "program test; var x : integer; begin x := " + strings.Repeat("(", depth) + "1" + strings.Repeat(")", depth) + " end."
+	}
+	nestedStmt := func(depth int) string {
+		return "program test; var x : integer; begin " + strings.Repeat("begin ", depth) + "x := 1" + strings.Repeat(" end", depth) + " end."
+	}
+
+	_, err := Parse("test.pas", nestedExpr(500))
+	require.NoError(t, err)
+
+	_, err = Parse("test.pas", nestedExpr(100000))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expression too deeply nested")
+
+	_, err = Parse("test.pas", "program test; var b : boolean; begin b := "+strings.Repeat("not ", 100000)+"true end.")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expression too deeply nested")
+
+	_, err = Parse("test.pas", nestedStmt(100000))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "statement too deeply nested")
+
+	_, err = Parse("test.pas", nestedExpr(50), WithMaxNestingDepth(20))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expression too deeply nested")
+
+	_, err = Parse("test.pas", nestedExpr(5000), WithMaxNestingDepth(10000))
+	require.NoError(t, err)
+}
+
+func TestParserSynthesizedTypeNames(t *testing.T) {
+	p := newParser("test.pas", "")
+
+	outer := &Block{Types: []*TypeDefinition{{Name: "anon_type_1", Type: &IntegerType{}}}}
+	inner := &Block{Parent: outer, Variables: []*Variable{{Name: "anon_type_2", Type: &IntegerType{}}}}
+
+	require.Equal(t, "anon_type_3", p.synthesizedTypeName(inner))
+	require.Equal(t, "anon_type_4", p.synthesizedTypeName(inner))
+	require.Equal(t, "anon_type_5", p.synthesizedTypeName(&Block{}))
 }
@@ -163,6 +163,10 @@ type ProcedureCallStatement struct {
 	Name         string
 	ActualParams []Expression
 	FormalParams []*FormalParameter
+
+	// The procedure that is called, which tells builtin procedures apart from
+	// user-declared procedures of the same name.
+	Routine *Routine
 }
 
 func (s *ProcedureCallStatement) Type() StatementType {
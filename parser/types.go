@@ -93,7 +93,8 @@ func (t *PointerType) IsCompatibleWith(dt DataType, assignmentCompatible bool) b
 		return true
 	}
 
-	return t.Type_.IsCompatibleWith(o.Type_, assignmentCompatible)
+	// pointers are only compatible if they point to the same type.
+	return t.Type_.Equals(o.Type_)
 }
 
 // SubrangeType describes a type that is a range with a lower and an upper boundary of an integral type.
@@ -112,9 +113,10 @@ func (t *SubrangeType) TypeString() string {
 	lb := fmt.Sprint(t.LowerBound)
 	ub := fmt.Sprint(t.UpperBound)
 	if et, ok := t.Type_.(*EnumType); ok {
-		if t.LowerBound >= 0 && t.LowerBound < len(et.Identifiers) && t.UpperBound >= 0 && t.UpperBound < len(et.Identifiers) {
-			lb = et.Identifiers[t.LowerBound]
-			ub = et.Identifiers[t.UpperBound]
+		lbIdent, lbOk := et.Identifier(t.LowerBound)
+		ubIdent, ubOk := et.Identifier(t.UpperBound)
+		if lbOk && ubOk {
+			lb, ub = lbIdent, ubIdent
 		}
 	}
 	if _, ok := t.Type_.(*CharType); ok {
@@ -168,9 +170,43 @@ func (t *SubrangeType) IsCompatibleWith(dt DataType, assignmentCompatible bool)
 
 // EnumType describes an enumerated type, consisting of a list of identifiers.
 type EnumType struct {
-	// List of identifiers. Their indexes are equal to their respective integer values.
+	// List of identifiers. Unless Values is set, their indexes are equal to their
+	// respective integer values.
 	Identifiers []string
-	name        string
+
+	// If not nil, the explicitly declared integer values of the identifiers, in
+	// ascending order.
+	Values []int
+
+	name string
+}
+
+// Value returns the integer value of the identifier at index idx.
+func (t *EnumType) Value(idx int) int {
+	if t.Values == nil {
+		return idx
+	}
+	return t.Values[idx]
+}
+
+// Identifier returns the identifier with the integer value v.
+func (t *EnumType) Identifier(v int) (string, bool) {
+	for idx, ident := range t.Identifiers {
+		if t.Value(idx) == v {
+			return ident, true
+		}
+	}
+	return "", false
+}
+
+// MaxValue returns the largest integer value of the enumerated type's identifiers.
+func (t *EnumType) MaxValue() int {
+	return t.Value(len(t.Identifiers) - 1)
+}
+
+// MinValue returns the smallest integer value of the enumerated type's identifiers.
+func (t *EnumType) MinValue() int {
+	return t.Value(0)
 }
 
 func (t *EnumType) TypeString() string {
@@ -211,12 +247,13 @@ func (t *EnumType) Resolve(_ *Block) error {
 }
 
 func (t *EnumType) IsCompatibleWith(dt DataType, assignmentCompatible bool) bool {
-	_, ok := dt.(*EnumType)
-	if !ok {
-		return false
+	switch ot := dt.(type) {
+	case *EnumType:
+		return t.Equals(ot)
+	case *SubrangeType:
+		return t.Equals(ot.Type_)
 	}
-	// TODO: determine compatibility of enum types.
-	return true
+	return false
 }
 
 // ArrayType describes an array type. IndexTypes contains the types of the dimensions
@@ -289,10 +326,8 @@ func (t *ArrayType) Resolve(b *Block) error {
 
 func (t *ArrayType) IsCompatibleWith(dt DataType, assignmentCompatible bool) bool {
 	// special case: array of char is compatible with string.
-	if len(t.IndexTypes) == 1 && t.ElementType.Equals(&CharType{}) {
-		if dt.IsCompatibleWith(&StringType{}, assignmentCompatible) {
-			return true
-		}
+	if st, ok := dt.(*StringType); ok {
+		return stringCompatibleWithCharArray(st, t)
 	}
 
 	o, ok := dt.(*ArrayType)
@@ -358,6 +393,27 @@ func (t *RecordType) findField(name string) *RecordField {
 	return nil
 }
 
+// allFields returns the fixed fields, the tag field and the fields of all variants
+// of the record type.
+func (t *RecordType) allFields() []*RecordField {
+	fields := append([]*RecordField{}, t.Fields...)
+
+	if t.VariantField != nil {
+		if t.VariantField.TagField != "" {
+			fields = append(fields, &RecordField{
+				Identifier: t.VariantField.TagField,
+				Type:       t.VariantField.Type,
+			})
+		}
+
+		for _, variant := range t.VariantField.Variants {
+			fields = append(fields, variant.Fields.allFields()...)
+		}
+	}
+
+	return fields
+}
+
 func (t *RecordType) TypeString() string {
 	var buf strings.Builder
 	if t.Packed {
@@ -605,9 +661,15 @@ func (t *CharType) IsCompatibleWith(dt DataType, assignmentCompatible bool) bool
 // StringType describes the string type.
 type StringType struct {
 	name string
+
+	// If not 0, the maximum length of the string, as declared by string[n].
+	MaxLength int
 }
 
 func (t *StringType) TypeString() string {
+	if t.MaxLength > 0 {
+		return fmt.Sprintf("string[%d]", t.MaxLength)
+	}
 	return "string"
 }
 
@@ -635,17 +697,47 @@ func (t *StringType) IsCompatibleWith(dt DataType, assignmentCompatible bool) bo
 		return true
 	}
 
-	arrType, ok := dt.(*ArrayType)
-	if !ok {
+	// arrays of char are compatible with strings, as long as a string with a
+	// maximum length has the same length as the array.
+	return stringCompatibleWithCharArray(t, dt)
+}
+
+// stringCompatibleWithCharArray returns true if dt is an array of char that is
+// compatible with the string type st.
+func stringCompatibleWithCharArray(st *StringType, dt DataType) bool {
+	if !isCharArray(dt) {
 		return false
 	}
 
-	// arrays of char are compatible with strings.
-	if len(arrType.IndexTypes) == 1 && arrType.ElementType.Equals(&CharType{}) {
+	if st.MaxLength == 0 {
 		return true
 	}
 
-	return false
+	length, ok := charArrayLength(dt.(*ArrayType))
+	return ok && length == st.MaxLength
+}
+
+// charArrayLength returns the number of elements of an array of char, if its index
+// type is a subrange.
+func charArrayLength(arrType *ArrayType) (int, bool) {
+	indexType, ok := arrType.IndexTypes[0].(*SubrangeType)
+	if !ok {
+		return 0, false
+	}
+	return indexType.UpperBound - indexType.LowerBound + 1, true
+}
+
+// indexTypeLength returns the number of elements that an array index type spans.
+// Enumerated types, including boolean, span all values from their smallest to their
+// largest value.
+func indexTypeLength(indexType DataType) (int, bool) {
+	switch it := indexType.(type) {
+	case *SubrangeType:
+		return it.UpperBound - it.LowerBound + 1, true
+	case *EnumType:
+		return it.MaxValue() - it.MinValue() + 1, true
+	}
+	return 0, false
 }
 
 // RealType describes the real type.
@@ -1014,6 +1106,39 @@ func (l *EnumValueLiteral) String() string {
 	return l.Symbol
 }
 
+// ArrayLiteral describes the value of a typed constant of an array type.
+// Multi-dimensional arrays are represented by nested array literals.
+type ArrayLiteral struct {
+	// The array type of the literal. For nested literals, this is the array
+	// type of the remaining dimensions.
+	Type_ DataType
+
+	// The values of the array elements, in index order.
+	Elements []ConstantLiteral
+}
+
+func (l *ArrayLiteral) ConstantType() DataType {
+	return l.Type_
+}
+
+func (l *ArrayLiteral) Negate() (ConstantLiteral, error) {
+	return nil, errors.New("can't negate array literal")
+}
+
+func (l *ArrayLiteral) String() string {
+	var buf strings.Builder
+
+	buf.WriteString("(")
+	for idx, elem := range l.Elements {
+		if idx > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(elem.String())
+	}
+	buf.WriteString(")")
+	return buf.String()
+}
+
 func exprCompatible(t DataType, expr Expression) bool {
 	if t.IsCompatibleWith(expr.Type(), false) {
 		return true
@@ -1038,11 +1163,129 @@ func labelCompatibleWithType(label ConstantLiteral, typ DataType) bool {
 		return true
 	}
 
+	// labels of a subrange type need to be of its base type, and within the subrange.
+	if st, ok := typ.(*SubrangeType); ok && st.Type_ != nil {
+		if !labelCompatibleWithType(label, st.Type_) {
+			return false
+		}
+		value, ok := ordinalValue(label)
+		return ok && st.within(value)
+	}
+
 	//fmt.Printf("label type %s, expression type is %s\n", label.ConstantType().Type(), typ.Type())
 
 	return false
 }
 
+// ordinalValue returns the ordinal value of an integer, char or enum value literal.
+func ordinalValue(label ConstantLiteral) (int, bool) {
+	switch l := label.(type) {
+	case *IntegerLiteral:
+		return l.Value, true
+	case *CharLiteral:
+		return int(l.Value), true
+	case *EnumValueLiteral:
+		return l.Value, true
+	case *StringLiteral:
+		if l.IsCharLiteral() {
+			return int(l.Value[0]), true
+		}
+	}
+	return 0, false
+}
+
+// constantOrdinalValue returns the ordinal value of an integer, char or enum value
+// expression, together with its textual representation for use in error messages.
+// Constants are resolved within block b, and integer expressions that only consist
+// of constants are folded.
+func constantOrdinalValue(b *Block, expr Expression) (int, string, bool) {
+	switch e := expr.(type) {
+	case *IntegerExpr:
+		return e.Value, fmt.Sprint(e.Value), true
+	case *CharExpr:
+		return int(e.Value), toCharLiteral(e.Value), true
+	case *EnumValueExpr:
+		return e.Value, e.Name, true
+	case *ConstantExpr:
+		decl := b.findConstantDeclaration(e.Name)
+		if decl == nil {
+			return 0, "", false
+		}
+		switch lit := decl.Value.(type) {
+		case *IntegerLiteral:
+			return lit.Value, fmt.Sprint(lit.Value), true
+		case *CharLiteral:
+			return int(lit.Value), toCharLiteral(lit.Value), true
+		case *EnumValueLiteral:
+			return lit.Value, lit.Symbol, true
+		}
+	case *SubExpr:
+		return constantOrdinalValue(b, e.Expr)
+	case *SimpleExpr:
+		if len(e.Next) == 0 && e.Sign != "-" {
+			return constantOrdinalValue(b, e.First)
+		}
+		value, ok := constantIntegerValue(b, e)
+		return value, fmt.Sprint(value), ok
+	case *TermExpr:
+		if len(e.Next) == 0 {
+			return constantOrdinalValue(b, e.First)
+		}
+		value, ok := constantIntegerValue(b, e)
+		return value, fmt.Sprint(value), ok
+	}
+	return 0, "", false
+}
+
+// constantIntegerValue folds an integer expression that only consists of constants
+// into its value.
+func constantIntegerValue(b *Block, expr Expression) (int, bool) {
+	switch e := expr.(type) {
+	case *SimpleExpr:
+		value, ok := constantIntegerValue(b, e.First)
+		if !ok {
+			return 0, false
+		}
+		if e.Sign == "-" {
+			value = -value
+		}
+		for _, add := range e.Next {
+			if value, ok = foldConstantOperation(b, string(add.Operator), value, add.Term); !ok {
+				return 0, false
+			}
+		}
+		return value, true
+	case *TermExpr:
+		value, ok := constantIntegerValue(b, e.First)
+		if !ok {
+			return 0, false
+		}
+		for _, mul := range e.Next {
+			if value, ok = foldConstantOperation(b, string(mul.Operator), value, mul.Factor); !ok {
+				return 0, false
+			}
+		}
+		return value, true
+	}
+
+	value, _, ok := constantOrdinalValue(b, expr)
+	return value, ok && isIntegerType(expr.Type())
+}
+
+// foldConstantOperation applies the operator to an integer value and the folded value of an
+// integer expression.
+func foldConstantOperation(b *Block, operator string, left int, rightExpr Expression) (int, bool) {
+	right, ok := constantIntegerValue(b, rightExpr)
+	if !ok {
+		return 0, false
+	}
+	folded, err := foldIntegerConstants(operator, &IntegerLiteral{Value: left}, &IntegerLiteral{Value: right})
+	if err != nil {
+		return 0, false
+	}
+	return folded.(*IntegerLiteral).Value, true
+}
+
 func typesCompatibleForAssignment(lt, rt DataType) bool {
 	if lt.Equals(rt) {
 		return true
@@ -1074,7 +1317,11 @@ func typesCompatibleForAssignment(lt, rt DataType) bool {
 		return true
 	}
 
-	if isCharArray(lt) && rt.Equals(&StringType{}) {
+	if st, ok := rt.(*StringType); ok && stringCompatibleWithCharArray(st, lt) {
+		return true
+	}
+
+	if st, ok := lt.(*StringType); ok && stringCompatibleWithCharArray(st, rt) {
 		return true
 	}
 
@@ -1084,6 +1331,10 @@ func typesCompatibleForAssignment(lt, rt DataType) bool {
 		}
 	}
 
+	if st, ok := rt.(*SubrangeType); ok && st.Type_ != nil && lt.Equals(st.Type_) {
+		return true
+	}
+
 	// TODO: implement more cases of compatibility
 
 	return false
@@ -7,21 +7,31 @@ import (
 	"github.com/akrennmair/pascal/parser"
 )
 
-func toGoTypeDef(typeDef *parser.TypeDefinition) string {
+func (g *generator) toGoTypeDef(typeDef *parser.TypeDefinition) string {
 	var buf strings.Builder
 
 	buf.WriteString(typeDef.Name)
 	buf.WriteString(" ")
-	buf.WriteString(toGoTypeExcludeTypeName(typeDef.Type, typeDef.Name))
+	if st, ok := typeDef.Type.(*parser.SubrangeType); ok && enumSubrangeBase(st) != "" {
+		// subranges of enum types are aliases so that they can be used interchangeably with the enum type's values.
+		buf.WriteString("= ")
+		buf.WriteString(enumSubrangeBase(st))
+		return buf.String()
+	}
+	if rec, ok := typeDef.Type.(*parser.RecordType); ok {
+		buf.WriteString(g.recordTypeToGoType(rec, typeDef.Name))
+		return buf.String()
+	}
+	buf.WriteString(g.toGoTypeExcludeTypeName(typeDef.Type, typeDef.Name))
 
 	return buf.String()
 }
 
-func toGoType(typ parser.DataType) string {
-	return toGoTypeExcludeTypeName(typ, "")
+func (g *generator) toGoType(typ parser.DataType) string {
+	return g.toGoTypeExcludeTypeName(typ, "")
 }
 
-func toGoTypeExcludeTypeName(typ parser.DataType, excludeTypeName string) string {
+func (g *generator) toGoTypeExcludeTypeName(typ parser.DataType, excludeTypeName string) string {
 	switch dt := typ.(type) {
 	case *parser.IntegerType:
 		return "int"
@@ -31,7 +41,7 @@ func toGoTypeExcludeTypeName(typ parser.DataType, excludeTypeName string) string
 		if name := typ.TypeName(); name != "" {
 			return name
 		}
-		return recordTypeToGoType(dt)
+		return g.recordTypeToGoType(dt, "")
 	case *parser.StringType:
 		return "string"
 	case *parser.CharType:
@@ -45,27 +55,42 @@ func toGoTypeExcludeTypeName(typ parser.DataType, excludeTypeName string) string
 			return name
 		}
 
-		if dt.TargetName != "" {
+		if dt.TargetName != "" && !isPredeclaredGoType(dt.Type_) {
 			return "*" + dt.TargetName
 		}
-		return "*" + toGoType(dt.Type_)
+		return "*" + g.toGoType(dt.Type_)
 	case *parser.ArrayType:
 		var buf strings.Builder
 		for _, indexType := range dt.IndexTypes {
 			buf.WriteString("[")
-			srt, ok := indexType.(*parser.SubrangeType)
-			if ok {
-				buf.WriteString(fmt.Sprintf("%d", srt.UpperBound-srt.LowerBound+1))
+			switch it := indexType.(type) {
+			case *parser.SubrangeType:
+				buf.WriteString(fmt.Sprintf("%d", it.UpperBound-it.LowerBound+1))
+			case *parser.EnumType:
+				// enums are indexed by their value minus their smallest value, which makes this 2 for booleans.
+				buf.WriteString(fmt.Sprintf("%d", it.MaxValue()-it.MinValue()+1))
+			case *parser.CharType:
+				// chars are bytes, so arrays indexed by them have an element for every byte value.
+				buf.WriteString("256")
 			} // TODO: handle other index types.
 			buf.WriteString("]")
 		}
-		buf.WriteString(toGoType(dt.ElementType))
+		buf.WriteString(g.toGoType(dt.ElementType))
 		return buf.String()
 	case *parser.SubrangeType:
 		if name := typ.TypeName(); name != "" && name != excludeTypeName {
 			return name
 		}
 
+		if enumName := enumSubrangeBase(dt); enumName != "" {
+			return enumName
+		}
+
+		if _, isChar := dt.Type_.(*parser.CharType); isChar {
+			// subranges of char are bytes so that they can be used interchangeably with chars.
+			return "byte"
+		}
+
 		return "int" // Go doesn't have subrange types, so that's the closest we can translate them to.
 	case *parser.EnumType:
 		if parser.IsBooleanType(typ) {
@@ -78,9 +103,12 @@ func toGoTypeExcludeTypeName(typ parser.DataType, excludeTypeName string) string
 
 		return "int" // Go doesn't have enum types, so we just define it as an alias to int, and declare constants and a string conversion method.
 	case *parser.SetType:
-		return fmt.Sprintf("system.SetType[%s]", toGoType(dt.ElementType))
+		return fmt.Sprintf("%s[%s]", g.system("SetType"), g.toGoType(dt.ElementType))
 	case *parser.FileType:
-		return fmt.Sprintf("system.FileType[%s]", toGoType(dt.ElementType))
+		if parser.IsTextType(dt) {
+			return g.system("TextFile")
+		}
+		return fmt.Sprintf("%s[%s]", g.system("FileType"), g.toGoType(dt.ElementType))
 	case *parser.ProcedureType:
 		var buf strings.Builder
 		buf.WriteString("func(")
@@ -91,7 +119,7 @@ func toGoTypeExcludeTypeName(typ parser.DataType, excludeTypeName string) string
 			if param.VariableParameter {
 				buf.WriteString("*")
 			}
-			buf.WriteString(toGoType(param.Type))
+			buf.WriteString(g.toGoType(param.Type))
 		}
 		buf.WriteString(")")
 		return buf.String()
@@ -105,53 +133,233 @@ func toGoTypeExcludeTypeName(typ parser.DataType, excludeTypeName string) string
 			if param.VariableParameter {
 				buf.WriteString("*")
 			}
-			buf.WriteString(toGoType(param.Type))
+			buf.WriteString(g.toGoType(param.Type))
 		}
 		buf.WriteString(") ")
-		buf.WriteString(toGoType(dt.ReturnType))
+		buf.WriteString(g.toGoType(dt.ReturnType))
 		return buf.String()
 	}
 	return fmt.Sprintf("bug: unhandled type %T", typ)
 }
 
+// isPredeclaredGoType returns true if dt is always translated to a predeclared Go type,
+// regardless of whether it has a type name. Pointers to such types need to point to the
+// predeclared Go type, too, as all values of the type are declared with it.
+func isPredeclaredGoType(dt parser.DataType) bool {
+	switch dt.(type) {
+	case *parser.IntegerType, *parser.RealType, *parser.StringType:
+		return true
+	}
+	return isBooleanType(dt)
+}
+
+// enumSubrangeBase returns the name of the enum type that the subrange type is
+// a subrange of, or an empty string if it isn't a subrange of a named enum type.
+func enumSubrangeBase(st *parser.SubrangeType) string {
+	if et, ok := st.Type_.(*parser.EnumType); ok && !parser.IsBooleanType(et) {
+		return et.TypeName()
+	}
+	return ""
+}
+
+// isEnumSubrangeOf returns true if typ is a subrange of the named enum type enumType.
+func isEnumSubrangeOf(typ parser.DataType, enumType parser.DataType) bool {
+	st, ok := typ.(*parser.SubrangeType)
+	return ok && enumSubrangeBase(st) != "" && st.Type_.Equals(enumType)
+}
+
+// sortTypeDefs sorts type definitions so that every type is defined after the
+// types that it refers to. This is required as the type definitions are emitted
+// within a function, where Go doesn't allow referring to types that are defined
+// further below. Otherwise, the original order of the type definitions is preserved.
 func sortTypeDefs(typeDefs []*parser.TypeDefinition) []*parser.TypeDefinition {
+	typeDefsByName := make(map[string]*parser.TypeDefinition)
+	for _, typeDef := range typeDefs {
+		typeDefsByName[typeDef.Name] = typeDef
+	}
+
 	var (
-		newTypeList   []*parser.TypeDefinition
-		typesToAppend []*parser.TypeDefinition
+		sorted  []*parser.TypeDefinition
+		visited = make(map[string]bool)
+		visit   func(typeDef *parser.TypeDefinition)
 	)
 
+	visit = func(typeDef *parser.TypeDefinition) {
+		if visited[typeDef.Name] {
+			return
+		}
+		visited[typeDef.Name] = true
+
+		deps := make(map[string]bool)
+		typeDependencies(typeDef.Type, deps)
+		for _, dep := range typeDefs { // iterate over type definitions rather than the map to keep the order deterministic.
+			if deps[dep.Name] && dep.Name != typeDef.Name {
+				visit(typeDefsByName[dep.Name])
+			}
+		}
+
+		sorted = append(sorted, typeDef)
+	}
+
+	for _, typeDef := range typeDefs {
+		visit(typeDef)
+	}
+
+	return sorted
+}
+
+// hasCyclicTypes returns true if the type definitions refer to each other in a cycle that
+// can't be declared within a function, such as two records with pointers to each other. A
+// record with a pointer to itself is not such a cycle, as fieldTypeToGoType refers to the
+// record directly.
+func hasCyclicTypes(typeDefs []*parser.TypeDefinition) bool {
+	typeDefsByName := make(map[string]*parser.TypeDefinition)
 	for _, typeDef := range typeDefs {
-		if pt, ok := typeDef.Type.(*parser.PointerType); ok {
-			if pt.TargetName != "" {
-				typesToAppend = append(typesToAppend, typeDef)
+		typeDefsByName[typeDef.Name] = typeDef
+	}
+
+	declared := make(map[string]bool)
+	for _, typeDef := range sortTypeDefs(typeDefs) {
+		deps := make(map[string]bool)
+		typeDependencies(typeDef.Type, deps)
+		for dep := range deps {
+			depDef, ok := typeDefsByName[dep]
+			if !ok || dep == typeDef.Name || declared[dep] {
+				continue
+			}
+			if pt, ok := depDef.Type.(*parser.PointerType); ok && pt.TargetName == typeDef.Name {
 				continue
 			}
+			return true
+		}
+		declared[typeDef.Name] = true
+	}
+
+	return false
+}
+
+// localTypes returns the type definitions of a block that are declared within the function
+// that the block is transpiled to. Cyclic type definitions of the program block are declared
+// at package level instead, where Go allows types to refer to types declared further below.
+func localTypes(block *parser.Block) []*parser.TypeDefinition {
+	if !hasCyclicTypes(block.Types) {
+		return block.Types
+	}
+	if block.Routine != nil {
+		panic(fmt.Errorf("mutually recursive types are only supported in the program block"))
+	}
+	return nil
+}
+
+// hasTypedConstants returns true if typed constants are declared in the block.
+func hasTypedConstants(block *parser.Block) bool {
+	return len(typedConstants(block)) > 0
+}
+
+// typedConstants returns the typed constants that are declared in the block.
+func typedConstants(block *parser.Block) []*parser.Variable {
+	var constants []*parser.Variable
+	for _, variable := range block.Variables {
+		if variable.InitialValue != nil {
+			constants = append(constants, variable)
+		}
+	}
+	return constants
+}
+
+// withoutTypedConstants returns the block without its typed constants. Typed constants
+// keep their value between calls of the routine that they are declared in, so they are
+// declared in a closure around the routine, or at package level for top-level routines.
+func withoutTypedConstants(block *parser.Block) *parser.Block {
+	body := *block
+	body.Variables = nil
+	for _, variable := range block.Variables {
+		if variable.InitialValue == nil {
+			body.Variables = append(body.Variables, variable)
+		}
+	}
+	return &body
+}
+
+// hoistTypedConstants returns the typed constants of a top-level routine, which are
+// declared at package level so that they keep their value between calls of the routine.
+// They are renamed to the routine's name followed by their own name, which is then used
+// to refer to them.
+func (g *generator) hoistTypedConstants(routine *parser.Routine) []*parser.Variable {
+	var hoisted []*parser.Variable
+	for _, constant := range typedConstants(routine.Block) {
+		name := g.routineName(routine) + "_" + constant.Name
+		g.hoistedConstants[constant] = name
+		hoisted = append(hoisted, &parser.Variable{Name: name, Type: constant.Type, InitialValue: constant.InitialValue})
+	}
+	return hoisted
+}
+
+// typeDependencies collects the names of all types that the Go type of dt refers to.
+func typeDependencies(dt parser.DataType, deps map[string]bool) {
+	switch t := dt.(type) {
+	case *parser.RecordType:
+		for _, field := range t.Fields {
+			typeReference(field.Type, deps)
+		}
+		if t.VariantField != nil {
+			if t.VariantField.Type != nil {
+				typeReference(t.VariantField.Type, deps)
+			}
+			for _, variant := range t.VariantField.Variants {
+				typeDependencies(variant.Fields, deps)
+			}
+		}
+	case *parser.ArrayType:
+		typeReference(t.ElementType, deps)
+	case *parser.SetType:
+		if t.ElementType != nil {
+			typeReference(t.ElementType, deps)
+		}
+	case *parser.FileType:
+		typeReference(t.ElementType, deps)
+	case *parser.PointerType:
+		if t.TargetName != "" {
+			deps[t.TargetName] = true
+		} else if t.Type_ != nil {
+			typeReference(t.Type_, deps)
+		}
+	case *parser.SubrangeType:
+		if enumName := enumSubrangeBase(t); enumName != "" {
+			deps[enumName] = true
 		}
-		newTypeList = append(newTypeList, typeDef)
 	}
+}
 
-	return append(newTypeList, typesToAppend...)
+func typeReference(dt parser.DataType, deps map[string]bool) {
+	if name := dt.TypeName(); name != "" {
+		deps[name] = true
+		return
+	}
+	typeDependencies(dt, deps)
 }
 
-func recordTypeToGoType(rec *parser.RecordType) string {
+// recordTypeToGoType returns the Go struct type for the record type rec. typeName is the name
+// of the type definition that rec belongs to, or an empty string if it is anonymous.
+func (g *generator) recordTypeToGoType(rec *parser.RecordType, typeName string) string {
 	var buf strings.Builder
 
 	buf.WriteString("struct {\n")
 
 	for _, field := range rec.Fields {
 		buf.WriteString("	")
-		buf.WriteString(field.Identifier)
+		buf.WriteString(fieldName(field.Identifier))
 		buf.WriteString(" ")
-		buf.WriteString(toGoType(field.Type))
+		buf.WriteString(g.fieldTypeToGoType(field.Type, typeName))
 		buf.WriteString("\n")
 	}
 
 	if rec.VariantField != nil {
 		if rec.VariantField.TagField != "" && rec.VariantField.Type != nil {
 			buf.WriteString("    ")
-			buf.WriteString(rec.VariantField.TagField)
+			buf.WriteString(fieldName(rec.VariantField.TagField))
 			buf.WriteString(" ")
-			buf.WriteString(toGoType(rec.VariantField.Type))
+			buf.WriteString(g.toGoType(rec.VariantField.Type))
 			buf.WriteString(" `pas2go:\"tagfield\"`")
 			buf.WriteString("\n")
 		}
@@ -162,9 +370,9 @@ func recordTypeToGoType(rec *parser.RecordType) string {
 			}
 			for _, field := range variant.Fields.Fields {
 				buf.WriteString("	")
-				buf.WriteString(field.Identifier)
+				buf.WriteString(fieldName(field.Identifier))
 				buf.WriteString(" ")
-				buf.WriteString(toGoType(field.Type))
+				buf.WriteString(g.fieldTypeToGoType(field.Type, typeName))
 				buf.WriteString(fmt.Sprintf(" `pas2go:\"caselabels,%s\"`", strings.Join(caseLabels, ",")))
 				buf.WriteString("\n")
 			}
@@ -176,7 +384,19 @@ func recordTypeToGoType(rec *parser.RecordType) string {
 	return buf.String()
 }
 
-func constantLiteral(cl parser.ConstantLiteral) string {
+// fieldTypeToGoType returns the Go type of a field of the record type named typeName.
+// A named pointer type that points back to the record, as in a linked list, is emitted
+// as a plain pointer to the record, as Go doesn't allow a type declared within a function
+// to refer to a type that is declared further below, and the pointer type can only be
+// declared after the record.
+func (g *generator) fieldTypeToGoType(dt parser.DataType, typeName string) string {
+	if pt, ok := dt.(*parser.PointerType); ok && typeName != "" && pt.TargetName == typeName {
+		return "*" + typeName
+	}
+	return g.toGoType(dt)
+}
+
+func (g *generator) constantLiteral(cl parser.ConstantLiteral) string {
 	switch lit := cl.(type) {
 	case *parser.IntegerLiteral:
 		if lit.Value < 0 {
@@ -186,41 +406,76 @@ func constantLiteral(cl parser.ConstantLiteral) string {
 	case *parser.StringLiteral:
 		return fmt.Sprintf("%q", lit.Value)
 	case *parser.RealLiteral:
-		sign := ""
-		if lit.Minus {
-			sign = "-"
-		}
-		realStr := fmt.Sprintf("%s%s.%se%d", sign, lit.BeforeComma, lit.AfterComma, lit.ScaleFactor)
-		if sign != "" {
-			return "(" + realStr + ")"
-		}
-		return realStr
+		return realLiteral(lit.Minus, lit.BeforeComma, lit.AfterComma, lit.ScaleFactor)
 	case *parser.EnumValueLiteral:
 		return lit.Symbol
 	case *parser.CharLiteral:
-		if lit.Value == '\'' {
-			return `'\''`
-		}
-		return fmt.Sprintf("'%c'", lit.Value)
+		return charLiteral(lit.Value)
+	case *parser.ArrayLiteral:
+		return g.toGoType(lit.Type_) + g.arrayLiteralElements(lit)
 	default:
 		return fmt.Sprintf("bug: unhandled constant literal type %T", cl)
 	}
 }
 
-func constantLiteralList(labels []parser.ConstantLiteral) string {
+// arrayLiteralElements returns the elements of an array literal in braces. The types
+// of nested array literals are elided, as Go allows this in composite literals.
+func (g *generator) arrayLiteralElements(lit *parser.ArrayLiteral) string {
+	var buf strings.Builder
+
+	buf.WriteString("{")
+	for idx, elem := range lit.Elements {
+		if idx > 0 {
+			buf.WriteString(", ")
+		}
+		if nested, ok := elem.(*parser.ArrayLiteral); ok {
+			buf.WriteString(g.arrayLiteralElements(nested))
+		} else {
+			buf.WriteString(g.constantLiteral(elem))
+		}
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// charLiteral returns a Go rune literal for a char. Quotes and control characters
+// are escaped.
+func charLiteral(c byte) string {
+	return fmt.Sprintf("%q", rune(c))
+}
+
+// realLiteral assembles a Go float literal from the textual parts of a Pascal
+// real literal, so that the value is preserved exactly as it was written. Parts
+// that are empty (e.g. in 5. or .5) are filled with 0 to always get a valid literal.
+func realLiteral(minus bool, beforeComma string, afterComma string, scaleFactor int) string {
+	if beforeComma == "" {
+		beforeComma = "0"
+	}
+	if afterComma == "" {
+		afterComma = "0"
+	}
+
+	realStr := fmt.Sprintf("%s.%se%d", beforeComma, afterComma, scaleFactor)
+	if minus {
+		return "(-" + realStr + ")"
+	}
+	return realStr
+}
+
+func (g *generator) constantLiteralList(labels []parser.ConstantLiteral) string {
 	var buf strings.Builder
 
 	for idx, l := range labels {
 		if idx > 0 {
 			buf.WriteString(", ")
 		}
-		buf.WriteString(constantLiteral(l))
+		buf.WriteString(g.constantLiteral(l))
 	}
 
 	return buf.String()
 }
 
-func formalParams(params []*parser.FormalParameter) string {
+func (g *generator) formalParams(params []*parser.FormalParameter) string {
 	var buf strings.Builder
 
 	for idx, param := range params {
@@ -232,13 +487,74 @@ func formalParams(params []*parser.FormalParameter) string {
 		if param.VariableParameter {
 			buf.WriteString("*")
 		}
-		buf.WriteString(toGoType(param.Type))
+		buf.WriteString(g.toGoType(param.Type))
+	}
+
+	return buf.String()
+}
+
+func paramNames(params []*parser.FormalParameter) string {
+	var buf strings.Builder
+
+	for idx, param := range params {
+		if idx > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(param.Name)
 	}
 
 	return buf.String()
 }
 
-func actualParams(params []parser.Expression, formalParams []*parser.FormalParameter) string {
+// exportedName returns the name of a routine as it is exported from a
+// transpiled library package.
+func exportedName(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// fieldName returns the name of a record field in the Go struct type of the record. Fields
+// are exported, so that the runtime can set them when records are read from files.
+func fieldName(name string) string {
+	return exportedName(name)
+}
+
+// exportedRoutineName returns the name that a routine is exported as from a transpiled
+// library package. As the program's statement part is exported as Main, a routine named
+// main is exported as Main_ instead.
+func exportedRoutineName(name string) string {
+	if exported := exportedName(name); exported != "Main" {
+		return exported
+	}
+	return "Main_"
+}
+
+// routineName returns the Go name of a routine. When routines are emitted as top-level
+// functions, global routines named main or init would clash with Go's special functions
+// of the same name, so they are renamed to main_ and init_.
+func (g *generator) routineName(routine *parser.Routine) string {
+	if g.HasTopLevelRoutines() && (routine.Name == "main" || routine.Name == "init") && g.globalRoutine(routine.Name) == routine {
+		return routine.Name + "_"
+	}
+	return routine.Name
+}
+
+// globalRoutine returns the procedure or function of the provided name that is declared
+// in the program block, or nil if there is none.
+func (g *generator) globalRoutine(name string) *parser.Routine {
+	for _, routine := range g.Block.Procedures {
+		if routine.Name == name {
+			return routine
+		}
+	}
+	for _, routine := range g.Block.Functions {
+		if routine.Name == name {
+			return routine
+		}
+	}
+	return nil
+}
+
+func (g *generator) actualParams(params []parser.Expression, formalParams []*parser.FormalParameter) string {
 	var buf strings.Builder
 
 	buf.WriteString("(")
@@ -248,9 +564,17 @@ func actualParams(params []parser.Expression, formalParams []*parser.FormalParam
 			buf.WriteString(", ")
 		}
 		if formalParams != nil && formalParams[idx].VariableParameter {
+			if !isAddressable(param) {
+				panic(fmt.Errorf("actual parameter %d for variable parameter %s is not addressable", idx+1, formalParams[idx].Name))
+			}
 			buf.WriteString("&")
 		}
-		buf.WriteString(toExpr(param))
+		if formalParams != nil && !formalParams[idx].VariableParameter && isReal(formalParams[idx].Type) && isIntegerValued(param.Type()) {
+			// Go doesn't widen integers to floats implicitly.
+			buf.WriteString("float64(" + g.toExpr(param) + ")")
+			continue
+		}
+		buf.WriteString(g.toExpr(param))
 	}
 
 	buf.WriteString(")")
@@ -258,6 +582,105 @@ func actualParams(params []parser.Expression, formalParams []*parser.FormalParam
 	return buf.String()
 }
 
+// writeParams returns the actual parameters of a write statement. Char arrays
+// written to standard output or a text file are converted to strings so that
+// they are printed as text.
+func (g *generator) writeParams(stmt *parser.WriteStatement) string {
+	if stmt.FileVar != nil && !isTextFile(stmt.FileVar) {
+		return g.actualParams(stmt.ActualParams, nil)
+	}
+
+	var buf strings.Builder
+
+	buf.WriteString("(")
+
+	for idx, param := range stmt.ActualParams {
+		if idx > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(g.writeParam(param))
+	}
+
+	if stmt.AppendNewLine && g.LineTerminator != "" {
+		if len(stmt.ActualParams) > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(fmt.Sprintf("%q", g.LineTerminator))
+	}
+
+	buf.WriteString(")")
+
+	return buf.String()
+}
+
+// writeFunc returns the name of the function that implements a write or writeln statement.
+// With a custom line terminator, writeln statements are implemented by Write, and the line
+// terminator is written as the last parameter, as Writeln always terminates lines with "\n".
+func (g *generator) writeFunc(stmt *parser.WriteStatement) string {
+	if stmt.AppendNewLine && g.LineTerminator == "" {
+		return "Writeln"
+	}
+	return "Write"
+}
+
+// writeParam returns a parameter of write or writeln. Parameters with a field width
+// are formatted before they are written.
+func (g *generator) writeParam(param parser.Expression) string {
+	formatExpr, ok := param.(*parser.FormatExpr)
+	if ok {
+		param = formatExpr.Expr
+	}
+
+	expr := g.toExpr(param)
+	if isCharArray(param.Type()) {
+		expr = fmt.Sprintf("string(%s[:])", expr)
+	}
+
+	switch {
+	case !ok || formatExpr.Width == nil:
+		if width := g.defaultFieldWidth(param.Type()); width > 0 {
+			return fmt.Sprintf("%s(%s, %d)", g.system("Format"), expr, width)
+		}
+		return expr
+	case formatExpr.DecimalPlaces != nil:
+		return fmt.Sprintf("%s(%s, %s, %s)", g.system("FormatFixed"), expr, g.toExpr(formatExpr.Width), g.toExpr(formatExpr.DecimalPlaces))
+	default:
+		return fmt.Sprintf("%s(%s, %s)", g.system("Format"), expr, g.toExpr(formatExpr.Width))
+	}
+}
+
+// defaultFieldWidth returns the field width that values of the type are written with if
+// no field width is specified, or 0 if they are written without padding.
+func (g *generator) defaultFieldWidth(typ parser.DataType) int {
+	switch t := typ.(type) {
+	case *parser.IntegerType:
+		return g.IntegerFieldWidth
+	case *parser.SubrangeType:
+		if _, ok := t.Type_.(*parser.IntegerType); ok {
+			return g.IntegerFieldWidth
+		}
+	case *parser.RealType:
+		return g.RealFieldWidth
+	}
+	return 0
+}
+
+// isAddressable returns true if the address of the expression can be taken
+// in Go, which is required when passing it to a variable parameter.
+func isAddressable(expr parser.Expression) bool {
+	switch e := expr.(type) {
+	case *parser.VariableExpr, *parser.DerefExpr:
+		return true
+	case *parser.IndexedVariableExpr:
+		return isAddressable(e.Expr)
+	case *parser.FieldDesignatorExpr:
+		return isAddressable(e.Expr)
+	case *parser.SubExpr:
+		return isAddressable(e.Expr)
+	}
+	return false
+}
+
 var operatorMapping = map[string]string{
 	"=":   "==",
 	"<>":  "!=",
@@ -283,39 +706,154 @@ func translateOperator(op string) string {
 	return newOp
 }
 
-func toSetRelationalExpr(e *parser.RelationalExpr) string {
+// Precedences of Go operators, from lowest to highest. Operands that aren't
+// made up of any binary or unary operator have the highest precedence.
+const (
+	precOr = iota + 1
+	precAnd
+	precComparison
+	precAddition
+	precMultiplication
+	precUnary
+	precOperand
+)
+
+var operatorPrecedence = map[string]int{
+	"||": precOr,
+	"&&": precAnd,
+	"==": precComparison,
+	"!=": precComparison,
+	"<":  precComparison,
+	">":  precComparison,
+	"<=": precComparison,
+	">=": precComparison,
+	"+":  precAddition,
+	"-":  precAddition,
+	"*":  precMultiplication,
+	"/":  precMultiplication,
+	"%":  precMultiplication,
+}
+
+// exprPrecedence returns the precedence of the lowest-binding operator
+// at the top level of the transpiled expression.
+func exprPrecedence(expr parser.Expression) int {
+	switch e := expr.(type) {
+	case *parser.RelationalExpr:
+		if _, isSetType := e.Right.Type().(*parser.SetType); isSetType {
+			return precOperand
+		}
+		return precComparison
+	case *parser.SimpleExpr:
+		if _, isSetType := e.First.Type().(*parser.SetType); isSetType {
+			return precOperand
+		}
+		prec := exprPrecedence(e.First)
+		if e.Sign != "" {
+			prec = minPrecedence(prec, precAddition)
+		}
+		for _, next := range e.Next {
+			prec = minPrecedence(prec, operatorPrecedence[translateOperator(string(next.Operator))])
+		}
+		return prec
+	case *parser.TermExpr:
+		if _, isSetType := e.First.Type().(*parser.SetType); isSetType {
+			return precOperand
+		}
+		prec := exprPrecedence(e.First)
+		for _, next := range e.Next {
+			if _, ok := integerOperatorFuncs[next.Operator]; ok {
+				// everything to the left becomes an argument of the function call.
+				prec = precOperand
+				continue
+			}
+			prec = minPrecedence(prec, operatorPrecedence[translateOperator(string(next.Operator))])
+		}
+		return prec
+	case *parser.NotExpr:
+		return precUnary
+	case *parser.SubExpr:
+		return exprPrecedence(e.Expr)
+	}
+	return precOperand
+}
+
+func minPrecedence(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// operandExpr transpiles an operand of an operator with precedence prec. Operands are only
+// put in parentheses where Go's precedence rules require them, which is also the case for
+// operands without parentheses in Pascal, as e.g. relational operators bind more loosely
+// than or in Pascal, but not in Go. As Go's binary operators are left-associative, this
+// includes right operands whose precedence is the same as the operator's.
+func (g *generator) operandExpr(expr parser.Expression, prec int, right bool) string {
+	if subExpr, ok := expr.(*parser.SubExpr); ok {
+		expr = subExpr.Expr
+	}
+	innerPrec := exprPrecedence(expr)
+	if innerPrec < prec || (innerPrec == prec && right) {
+		return "(" + g.toExpr(expr) + ")"
+	}
+	return g.toExpr(expr)
+}
+
+// convertedOperandExpr transpiles an operand like operandExpr, and applies the type conversion
+// newType to it, if set. Operands of type conversions don't require any parentheses.
+func (g *generator) convertedOperandExpr(newType string, expr parser.Expression, prec int, right bool) string {
+	if newType != "" {
+		return applyTypeConversion(newType, g.toExpr(expr))
+	}
+	return g.operandExpr(expr, prec, right)
+}
+
+// firstOperandPrecedence returns the precedence of the operator that the first term of
+// a simple expression is an operand of, which is the unary operator if it has a sign.
+func firstOperandPrecedence(e *parser.SimpleExpr) int {
+	if e.Sign != "" {
+		return precUnary
+	}
+	if len(e.Next) > 0 {
+		return operatorPrecedence[translateOperator(string(e.Next[0].Operator))]
+	}
+	return 0
+}
+
+func (g *generator) toSetRelationalExpr(e *parser.RelationalExpr) string {
 	var buf strings.Builder
 
 	switch e.Operator {
 	case parser.OpEqual:
-		buf.WriteString(toExpr(e.Left))
+		buf.WriteString(g.operandExpr(e.Left, precOperand, false))
 		buf.WriteString(".Equals(")
-		buf.WriteString(toExpr(e.Right))
+		buf.WriteString(g.toExpr(e.Right))
 		buf.WriteString(")")
 	case parser.OpNotEqual:
-		buf.WriteString(toExpr(e.Left))
+		buf.WriteString(g.operandExpr(e.Left, precOperand, false))
 		buf.WriteString(".NotEquals(")
-		buf.WriteString(toExpr(e.Right))
+		buf.WriteString(g.toExpr(e.Right))
 		buf.WriteString(")")
 	case parser.OpLess:
-		buf.WriteString(toExpr(e.Left))
+		buf.WriteString(g.operandExpr(e.Left, precOperand, false))
 		buf.WriteString(".Less(")
-		buf.WriteString(toExpr(e.Right))
+		buf.WriteString(g.toExpr(e.Right))
 		buf.WriteString(")")
 	case parser.OpLessEqual:
-		buf.WriteString(toExpr(e.Left))
+		buf.WriteString(g.operandExpr(e.Left, precOperand, false))
 		buf.WriteString(".LessEqual(")
-		buf.WriteString(toExpr(e.Right))
+		buf.WriteString(g.toExpr(e.Right))
 		buf.WriteString(")")
 	case parser.OpGreater:
-		buf.WriteString(toExpr(e.Left))
+		buf.WriteString(g.operandExpr(e.Left, precOperand, false))
 		buf.WriteString(".Greater(")
-		buf.WriteString(toExpr(e.Right))
+		buf.WriteString(g.toExpr(e.Right))
 		buf.WriteString(")")
 	case parser.OpGreaterEqual:
-		buf.WriteString(toExpr(e.Left))
+		buf.WriteString(g.operandExpr(e.Left, precOperand, false))
 		buf.WriteString(".GreaterEqual(")
-		buf.WriteString(toExpr(e.Right))
+		buf.WriteString(g.toExpr(e.Right))
 		buf.WriteString(")")
 	default:
 		fmt.Fprintf(&buf, "BUG: unsupported set relational operator %s", string(e.Operator))
@@ -324,21 +862,25 @@ func toSetRelationalExpr(e *parser.RelationalExpr) string {
 	return buf.String()
 }
 
-func toSetSimpleExpr(e *parser.SimpleExpr) string {
+func (g *generator) toSetSimpleExpr(e *parser.SimpleExpr) string {
 	var buf strings.Builder
 
 	buf.WriteString(e.Sign) // TODO: this makes no sense, so how should we handle this?
 
-	buf.WriteString(toExpr(e.First))
+	buf.WriteString(g.operandExpr(e.First, precOperand, false))
 	for _, next := range e.Next {
 		switch next.Operator {
 		case parser.OperatorAdd:
 			buf.WriteString(".Union(")
-			buf.WriteString(toExpr(next.Term))
+			buf.WriteString(g.toExpr(next.Term))
 			buf.WriteString(")")
 		case parser.OperatorSubtract:
 			buf.WriteString(".Difference(")
-			buf.WriteString(toExpr(next.Term))
+			buf.WriteString(g.toExpr(next.Term))
+			buf.WriteString(")")
+		case parser.OperatorSymmetricDifference:
+			buf.WriteString(".SymmetricDifference(")
+			buf.WriteString(g.toExpr(next.Term))
 			buf.WriteString(")")
 		default:
 			fmt.Fprintf(&buf, "BUG: unsupported operator %s", string(next.Operator))
@@ -348,16 +890,16 @@ func toSetSimpleExpr(e *parser.SimpleExpr) string {
 	return buf.String()
 }
 
-func toSetTermExpr(e *parser.TermExpr) string {
+func (g *generator) toSetTermExpr(e *parser.TermExpr) string {
 	var buf strings.Builder
 
-	buf.WriteString(toExpr(e.First))
+	buf.WriteString(g.operandExpr(e.First, precOperand, false))
 
 	for _, next := range e.Next {
 		switch next.Operator {
 		case parser.OperatorMultiply:
 			buf.WriteString(".Intersection(")
-			buf.WriteString(toExpr(next.Factor))
+			buf.WriteString(g.toExpr(next.Factor))
 			buf.WriteString(")")
 		default:
 			fmt.Fprintf(&buf, "BUG: unsupported operator %s", string(next.Operator))
@@ -416,21 +958,29 @@ func applyTypeConversion(newType string, expr string) string {
 	return buf.String()
 }
 
-func toExpr(expr parser.Expression) string {
+func (g *generator) toExpr(expr parser.Expression) string {
 	switch e := expr.(type) {
 	case *parser.RelationalExpr:
-		leftExpr := toExpr(e.Left)
-		rightExpr := toExpr(e.Right)
 		if e.Operator == parser.OpIn {
+			leftExpr, rightExpr := g.toExpr(e.Left), g.operandExpr(e.Right, precOperand, false)
+			if elemType := e.Right.Type().(*parser.SetType).ElementType; elemType != nil {
+				if goType := g.toGoType(elemType); goType != g.toGoType(e.Left.Type()) {
+					leftExpr = goType + "(" + leftExpr + ")"
+				}
+			}
 			return rightExpr + ".In(" + leftExpr + ")"
 		}
 		if _, isSetType := e.Left.Type().(*parser.SetType); isSetType {
-			return toSetRelationalExpr(e)
+			return g.toSetRelationalExpr(e)
 		}
+		// Go accepts chained comparisons like a < b == c, but they are hard to read, so
+		// comparisons that are operands of another comparison are always put in parentheses.
+		leftExpr := g.operandExpr(e.Left, precComparison, true)
+		rightExpr := g.operandExpr(e.Right, precComparison, true)
 		if parser.IsBooleanType(e.Left.Type()) && parser.IsBooleanType(e.Right.Type()) {
 			if e.Operator == parser.OpGreater || e.Operator == parser.OpGreaterEqual || e.Operator == parser.OpLess || e.Operator == parser.OpLessEqual {
-				leftExpr = "system.BoolOrd(" + leftExpr + ")"
-				rightExpr = "system.BoolOrd(" + rightExpr + ")"
+				leftExpr = g.system("BoolOrd") + "(" + g.toExpr(e.Left) + ")"
+				rightExpr = g.system("BoolOrd") + "(" + g.toExpr(e.Right) + ")"
 			}
 		} else if isStringish(e.Left.Type()) && isStringish(e.Right.Type()) {
 			if isCharArray(e.Left.Type()) {
@@ -443,86 +993,83 @@ func toExpr(expr parser.Expression) string {
 		return leftExpr + " " + translateOperator(string(e.Operator)) + " " + rightExpr
 	case *parser.SimpleExpr:
 		if _, isSetType := e.First.Type().(*parser.SetType); isSetType {
-			return toSetSimpleExpr(e)
+			return g.toSetSimpleExpr(e)
 		}
 		var buf strings.Builder
 		buf.WriteString(e.Sign)
 		if len(e.Next) > 0 {
 			leftType, typeConv := findLeftTypeConversion(e.First, e.Next[0].Term)
-			buf.WriteString(applyTypeConversion(typeConv, toExpr(e.First)))
+			buf.WriteString(g.convertedOperandExpr(typeConv, e.First, firstOperandPrecedence(e), false))
 			for _, next := range e.Next {
 				typeConv := findTypeConversion2(leftType, next.Term)
-				buf.WriteString(translateOperator(string(next.Operator)))
-				buf.WriteString(applyTypeConversion(typeConv, toExpr(next.Term)))
+				op := translateOperator(string(next.Operator))
+				buf.WriteString(op)
+				buf.WriteString(g.convertedOperandExpr(typeConv, next.Term, operatorPrecedence[op], true))
 			}
 		} else {
-			buf.WriteString(toExpr(e.First))
-			for _, next := range e.Next {
-				typeConv := findTypeConversion(e.First, next.Term)
-				buf.WriteString(translateOperator(string(next.Operator)))
-				buf.WriteString(applyTypeConversion(typeConv, toExpr(next.Term)))
-			}
+			buf.WriteString(g.operandExpr(e.First, firstOperandPrecedence(e), false))
 		}
 
 		return buf.String()
 	case *parser.TermExpr:
 		if _, isSetType := e.First.Type().(*parser.SetType); isSetType {
-			return toSetTermExpr(e)
+			return g.toSetTermExpr(e)
 		}
 		var buf strings.Builder
 		if len(e.Next) > 0 {
 			leftType, typeConv := findLeftTypeConversion(e.First, e.Next[0].Factor)
-			buf.WriteString(applyTypeConversion(typeConv, toExpr(e.First)))
+			firstPrec := operatorPrecedence[translateOperator(string(e.Next[0].Operator))]
+			if _, ok := integerOperatorFuncs[e.Next[0].Operator]; ok {
+				firstPrec = 0
+			}
+			buf.WriteString(g.convertedOperandExpr(typeConv, e.First, firstPrec, false))
 			for _, next := range e.Next {
 				typeConv := findTypeConversion2(leftType, next.Factor)
-				buf.WriteString(translateOperator(string(next.Operator)))
-				buf.WriteString(applyTypeConversion(typeConv, toExpr(next.Factor)))
+				if funcName, ok := integerOperatorFuncs[next.Operator]; ok {
+					// Go's % differs from Pascal's mod for negative operands. The operators are
+					// translated to function calls, with everything to their left as the first
+					// argument, which preserves the left-to-right evaluation of the term.
+					left := buf.String()
+					buf.Reset()
+					buf.WriteString(g.system(funcName) + "(" + left + ", " + g.convertedOperandExpr(typeConv, next.Factor, 0, false) + ")")
+					continue
+				}
+				op := translateOperator(string(next.Operator))
+				buf.WriteString(op)
+				buf.WriteString(g.convertedOperandExpr(typeConv, next.Factor, operatorPrecedence[op], true))
 			}
 		} else {
-			buf.WriteString(toExpr(e.First))
-			for _, next := range e.Next {
-				typeConv := findTypeConversion(e.First, next.Factor)
-				buf.WriteString(translateOperator(string(next.Operator)))
-				buf.WriteString(applyTypeConversion(typeConv, toExpr(next.Factor)))
-			}
+			buf.WriteString(g.toExpr(e.First))
 		}
 
 		return buf.String()
 	case *parser.ConstantExpr:
-		if str, ok := getBuiltinConstant(e.Name); ok {
+		if str, ok := g.getBuiltinConstant(e.Name); ok {
 			return str
 		}
 		return e.Name
 	case *parser.VariableExpr:
-		return toVariableExpr(e)
+		return g.toVariableExpr(e)
 	case *parser.IntegerExpr:
 		if e.Value < 0 {
 			return fmt.Sprintf("(%d)", e.Value)
 		}
 		return fmt.Sprint(e.Value)
 	case *parser.RealExpr:
-		sign := ""
-		if e.Minus {
-			sign = "-"
-		}
-		realStr := fmt.Sprintf("%s%s.%se%d", sign, e.BeforeComma, e.AfterComma, e.ScaleFactor)
-		if sign != "" {
-			realStr = "(" + realStr + ")"
-		}
-		return realStr
+		return realLiteral(e.Minus, e.BeforeComma, e.AfterComma, e.ScaleFactor)
 	case *parser.StringExpr:
 		return fmt.Sprintf("%q", e.Value)
 	case *parser.NilExpr:
 		return "nil"
 	case *parser.NotExpr:
-		return "!" + toExpr(e.Expr)
+		return "!" + g.operandExpr(e.Expr, precUnary, false)
 	case *parser.SetExpr:
 		var buf strings.Builder
-		buf.WriteString("system.Set")
+		buf.WriteString(g.system("Set"))
 
 		if elemTyp := e.Type().(*parser.SetType).ElementType; elemTyp != nil {
 			buf.WriteString("[")
-			buf.WriteString(toGoType(elemTyp))
+			buf.WriteString(g.toGoType(elemTyp))
 			buf.WriteString("]")
 		}
 		buf.WriteString("(")
@@ -530,123 +1077,244 @@ func toExpr(expr parser.Expression) string {
 			if idx > 0 {
 				buf.WriteString(", ")
 			}
-			buf.WriteString(toExpr(expr))
+			buf.WriteString(g.toExpr(expr))
 		}
 		buf.WriteString(")")
 		return buf.String()
 	case *parser.RangeExpr:
 		var buf strings.Builder
-		buf.WriteString(fmt.Sprintf("system.Range[%s](", toGoType(e.LowerBound.Type())))
-		buf.WriteString(toExpr(e.LowerBound))
+		if parser.IsBooleanType(e.LowerBound.Type()) {
+			buf.WriteString(g.system("BoolRange") + "(")
+		} else {
+			buf.WriteString(fmt.Sprintf("%s[%s](", g.system("Range"), g.toGoType(e.LowerBound.Type())))
+		}
+		buf.WriteString(g.toExpr(e.LowerBound))
 		buf.WriteString(", ")
-		buf.WriteString(toExpr(e.UpperBound))
+		buf.WriteString(g.toExpr(e.UpperBound))
 		buf.WriteString(")")
 		return buf.String()
 	case *parser.SubExpr:
-		return "(" + toExpr(e.Expr) + ")"
+		// parentheses are only added by operandExpr where they are required.
+		return g.toExpr(e.Expr)
 	case *parser.IndexedVariableExpr:
 		var buf strings.Builder
-		buf.WriteString(toExpr(e.Expr))
+		buf.WriteString(g.toExpr(e.Expr))
 		for idx, idxExpr := range e.IndexExprs {
 			buf.WriteString("[")
-			buf.WriteString(toExpr(idxExpr))
-			if srt, ok := e.Expr.Type().(*parser.ArrayType).IndexTypes[idx].(*parser.SubrangeType); ok && srt.LowerBound != 0 {
+			indexType := e.Expr.Type().(*parser.ArrayType).IndexTypes[idx]
+			if parser.IsBooleanType(indexType) {
+				buf.WriteString(g.system("BoolOrd") + "(" + g.toExpr(idxExpr) + ")")
+			} else {
+				buf.WriteString(g.toExpr(idxExpr))
+			}
+			if lowerBound := indexLowerBound(indexType); lowerBound != 0 {
 				buf.WriteString("-(")
-				buf.WriteString(fmt.Sprint(srt.LowerBound))
+				buf.WriteString(fmt.Sprint(lowerBound))
 				buf.WriteString(")")
 			}
 			buf.WriteString("]")
 		}
 		return buf.String()
 	case *parser.FunctionCallExpr:
-		return toFunctionCallExpr(e)
+		return g.toFunctionCallExpr(e)
 	case *parser.FieldDesignatorExpr:
-		return toExpr(e.Expr) + "." + e.Field
+		return g.toExpr(e.Expr) + "." + fieldName(e.Field)
 	case *parser.EnumValueExpr:
 		return e.Name
 	case *parser.DerefExpr:
-		return "(*" + toExpr(e.Expr) + ")"
+		if isFile(e.Expr) {
+			return "(*" + g.toExpr(e.Expr) + ".Buffer())"
+		}
+		if g.CheckedPointers {
+			return "(*" + g.system("Deref") + "(" + g.toExpr(e.Expr) + "))"
+		}
+		return "(*" + g.toExpr(e.Expr) + ")"
+	case *parser.TypeCastExpr:
+		return g.toTypeCastExpr(e)
 	case *parser.FormatExpr:
 		// TODO: implement full formatting
-		return toExpr(e.Expr)
+		return g.toExpr(e.Expr)
 	case *parser.CharExpr:
-		if e.Value == '\'' {
-			return `'\''`
-		}
-		return fmt.Sprintf("'%c'", e.Value)
+		return charLiteral(e.Value)
 	default:
 		return fmt.Sprintf("bug: invalid expression type %T", expr)
 	}
 }
 
-func toVariableExpr(e *parser.VariableExpr) string {
+// toTypeCastExpr converts an ordinal value to another ordinal type. As Go doesn't allow
+// conversions between booleans and integers, booleans are converted via their ordinal value.
+func (g *generator) toTypeCastExpr(e *parser.TypeCastExpr) string {
+	expr := g.toExpr(e.Expr)
+	if parser.IsBooleanType(e.Expr.Type()) {
+		if parser.IsBooleanType(e.Type_) {
+			return expr
+		}
+		expr = g.system("BoolOrd") + "(" + expr + ")"
+	}
+
+	if parser.IsBooleanType(e.Type_) {
+		return "(" + expr + " != 0)"
+	}
+
+	goType := g.toGoType(e.Type_)
+	if goType == "int" && parser.IsBooleanType(e.Expr.Type()) {
+		return expr
+	}
+
+	return goType + "(" + expr + ")"
+}
+
+// integerOperatorFuncs maps the integer division operators to the functions that implement them.
+var integerOperatorFuncs = map[parser.MultiplicationOperator]string{
+	parser.OperatorDivide: "Div",
+	parser.OperatorModulo: "Mod",
+}
+
+func (g *generator) toVariableExpr(e *parser.VariableExpr) string {
 	if e.IsReturnValue {
+		if routine := g.globalRoutine(e.Name); routine != nil {
+			return g.routineName(routine) + "_"
+		}
 		return e.Name + "_"
 	}
 
+	if e.VarDecl == nil && e.ParamDecl == nil {
+		switch e.Type().(type) {
+		case *parser.ProcedureType, *parser.FunctionType:
+			if routine := g.globalRoutine(e.Name); routine != nil {
+				return g.routineName(routine)
+			}
+		}
+	}
+
 	if e.ParamDecl != nil && e.ParamDecl.VariableParameter {
 		return "(*" + e.Name + ")"
 	}
 
+	if name, ok := g.hoistedConstants[e.VarDecl]; ok {
+		return name
+	}
+
 	str := e.Name
 	varDecl := e.VarDecl
+	if varDecl != nil && parser.IsStandardFile(varDecl) {
+		return g.system(exportedName(str))
+	}
 	if varDecl != nil && varDecl.IsRecordField {
-		str = toExpr(varDecl.BelongsToExpr) + "." + str // TODO: add fix for when expression refers to variable parameter.
+		if alias, ok := g.withAliases[varDecl.BelongsToExpr]; ok {
+			str = alias + "." + fieldName(str)
+		} else {
+			str = g.toExpr(varDecl.BelongsToExpr) + "." + fieldName(str)
+		}
 	}
 
 	return str
 }
 
-func toFunctionCallExpr(e *parser.FunctionCallExpr) string {
+// hasWithAliases returns true if any of the record expressions of a with statement
+// is more complex than a plain variable, and is therefore accessed through an alias.
+func hasWithAliases(stmt *parser.WithStatement) bool {
+	for _, expr := range stmt.RecordExprs {
+		if _, isVariable := expr.(*parser.VariableExpr); !isVariable {
+			return true
+		}
+	}
+	return false
+}
+
+// declareWithAliases declares local variables that point to the records of a with statement
+// whose record expressions are more complex than a plain variable. Fields are then accessed
+// through these variables, so that the record expressions are only evaluated once.
+func (g *generator) declareWithAliases(stmt *parser.WithStatement) string {
+	var buf strings.Builder
+	for _, expr := range stmt.RecordExprs {
+		if _, isVariable := expr.(*parser.VariableExpr); isVariable {
+			continue
+		}
+		alias := fmt.Sprintf("_with%d", len(g.withAliases)+1)
+		fmt.Fprintf(&buf, "%s := &%s\n_ = %s\n", alias, g.toExpr(expr), alias)
+		g.withAliases[expr] = alias
+	}
+	return buf.String()
+}
+
+// indexLowerBound returns the smallest value of an array index type, which is the
+// value that is stored at index 0 of the Go array.
+func indexLowerBound(indexType parser.DataType) int {
+	switch it := indexType.(type) {
+	case *parser.SubrangeType:
+		return it.LowerBound
+	case *parser.EnumType:
+		return it.MinValue()
+	}
+	return 0
+}
+
+func (g *generator) toFunctionCallExpr(e *parser.FunctionCallExpr) string {
+	if !isBuiltinFunction(e) {
+		name := e.Name
+		if e.Routine != nil {
+			name = g.routineName(e.Routine)
+		}
+		return name + g.actualParams(e.ActualParams, e.FormalParams)
+	}
+
 	switch e.Name {
 	case "abs":
 		switch e.ActualParams[0].Type().(type) {
 		case *parser.IntegerType:
-			return "system.AbsInt" + actualParams(e.ActualParams, e.FormalParams)
+			return g.system("AbsInt") + g.actualParams(e.ActualParams, e.FormalParams)
 		case *parser.RealType:
-			return "system.AbsReal" + actualParams(e.ActualParams, e.FormalParams)
+			return g.system("AbsReal") + g.actualParams(e.ActualParams, e.FormalParams)
 		case *parser.SubrangeType:
-			return "system.AbsInt(" + toExpr(e.ActualParams[0]) + ")"
+			// subranges may be of a named Go type.
+			return g.system("AbsInt") + "(int(" + g.toExpr(e.ActualParams[0]) + "))"
 		}
 	case "arctan":
-		return "system.Arctan" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Arctan") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "cos":
-		return "system.Cos" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Cos") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "exp":
-		return "system.Exp" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Exp") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "frac":
-		return "system.Frac" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Frac") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "int":
-		return "system.Int" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Int") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "ln":
-		return "system.Exp" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Exp") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "pi":
-		return "system.Pi" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Pi") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "sin":
-		return "system.Sin" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Sin") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "sqr":
 		switch e.ActualParams[0].Type().(type) {
 		case *parser.IntegerType:
-			return "system.SqrInt" + actualParams(e.ActualParams, e.FormalParams)
+			return g.system("SqrInt") + g.actualParams(e.ActualParams, e.FormalParams)
 		case *parser.RealType:
-			return "system.Sqr" + actualParams(e.ActualParams, e.FormalParams)
+			return g.system("Sqr") + g.actualParams(e.ActualParams, e.FormalParams)
 		default:
 			return fmt.Sprintf("BUG: unexpected type %s", e.ActualParams[0].Type().TypeString())
 		}
 	case "sqrt":
-		return "system.Sqrt" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Sqrt") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "trunc":
-		return "system.Trunc" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Trunc") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "round":
-		return "system.Round" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Round") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "chr":
-		return "system.Chr" + actualParams(e.ActualParams, e.FormalParams)
+		if folded, ok := g.foldChrOrd(e); ok {
+			return folded
+		}
+		return g.system("Chr") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "odd":
-		return "system.Odd" + actualParams(e.ActualParams, e.FormalParams)
+		return g.system("Odd") + g.actualParams(e.ActualParams, e.FormalParams)
 	case "ord":
+		if folded, ok := g.foldChrOrd(e); ok {
+			return folded
+		}
 		param := e.ActualParams[0]
 		if parser.IsBooleanType(param.Type()) {
-			return "system.BoolOrd(" + toExpr(param) + ")"
+			return g.system("BoolOrd") + "(" + g.toExpr(param) + ")"
 		} else if se, ok := param.(*parser.StringExpr); ok {
 			param = &parser.CharExpr{
 				Value: se.Value[0],
@@ -654,20 +1322,77 @@ func toFunctionCallExpr(e *parser.FunctionCallExpr) string {
 		} else if ce, ok := param.(*parser.ConstantExpr); ok {
 			param = ce
 		}
-		return "int(" + toExpr(param) + ")"
+		return "int(" + g.toExpr(param) + ")"
 	case "succ":
 		if parser.IsBooleanType(e.ActualParams[0].Type()) {
-			return "system.BoolSucc(" + toExpr(e.ActualParams[0]) + ")"
+			return g.system("BoolSucc") + "(" + g.toExpr(e.ActualParams[0]) + ")"
 		}
-		return "(" + toExpr(e.ActualParams[0]) + " + 1)"
+		return g.ordinalOffset(e.ActualParams[0], "+")
+	case "eof":
+		return g.toExpr(e.ActualParams[0]) + ".Eof()"
+	case "eoln":
+		return g.toExpr(e.ActualParams[0]) + ".Eoln()"
+	case "filepos":
+		return g.toExpr(e.ActualParams[0]) + ".FilePos()"
+	case "filesize":
+		return g.toExpr(e.ActualParams[0]) + ".FileSize()"
+	case "length":
+		return "len(" + g.toExpr(e.ActualParams[0]) + ")"
 	case "pred":
 		if parser.IsBooleanType(e.ActualParams[0].Type()) {
-			return "system.BoolPred(" + toExpr(e.ActualParams[0]) + ")"
+			return g.system("BoolPred") + "(" + g.toExpr(e.ActualParams[0]) + ")"
+		}
+		return g.ordinalOffset(e.ActualParams[0], "-")
+	}
+
+	return e.Name + g.actualParams(e.ActualParams, e.FormalParams)
+}
+
+// foldChrOrd folds chr(ord(c)) to c if c is a constant char, and ord(chr(n)) to n if n
+// is an integer literal within the range of char, as both are identities.
+func (g *generator) foldChrOrd(e *parser.FunctionCallExpr) (string, bool) {
+	inner, ok := e.ActualParams[0].(*parser.FunctionCallExpr)
+	if !ok || !isBuiltinFunction(inner) || len(inner.ActualParams) != 1 {
+		return "", false
+	}
+
+	switch param := inner.ActualParams[0].(type) {
+	case *parser.CharExpr:
+		if e.Name == "chr" && inner.Name == "ord" {
+			return "byte(" + g.toExpr(param) + ")", true
+		}
+	case *parser.StringExpr:
+		if e.Name == "chr" && inner.Name == "ord" && len(param.Value) == 1 {
+			return "byte(" + g.toExpr(&parser.CharExpr{Value: param.Value[0]}) + ")", true
+		}
+	case *parser.ConstantExpr:
+		if e.Name == "chr" && inner.Name == "ord" && parser.IsCharType(param.Type()) {
+			return "byte(" + g.toExpr(param) + ")", true
+		}
+	case *parser.IntegerExpr:
+		if e.Name == "ord" && inner.Name == "chr" && param.Value >= 0 && param.Value <= 255 {
+			return g.toExpr(param), true
+		}
+	}
+
+	return "", false
+}
+
+// ordinalOffset returns the successor or predecessor of an ordinal value, depending on
+// the operator.
+func (g *generator) ordinalOffset(param parser.Expression, operator string) string {
+	expr := "(" + g.toExpr(param) + " " + operator + " 1)"
+
+	switch param.(type) {
+	case *parser.CharExpr, *parser.ConstantExpr:
+		if parser.IsCharType(param.Type()) {
+			// char literals and constants are untyped in Go, so the result needs
+			// to be converted to remain a char.
+			return "byte" + expr
 		}
-		return "(" + toExpr(e.ActualParams[0]) + " - 1)"
 	}
 
-	return e.Name + actualParams(e.ActualParams, e.FormalParams)
+	return expr
 }
 
 func generateEnumValue(enumValue *parser.EnumValue) string {
@@ -687,57 +1412,126 @@ func generateEnumValue(enumValue *parser.EnumValue) string {
 	return buf.String()
 }
 
-func isBuiltinProcedure(name string) bool {
-	return parser.FindBuiltinProcedure(name) != nil
+// isBuiltinProcedure returns true if the statement calls a builtin procedure rather than
+// a user-declared procedure of the same name.
+func isBuiltinProcedure(stmt *parser.ProcedureCallStatement) bool {
+	return stmt.Routine != nil && stmt.Routine.IsBuiltin()
+}
+
+// isBuiltinFunction returns true if the expression calls a builtin function rather than
+// a user-declared function of the same name.
+func isBuiltinFunction(e *parser.FunctionCallExpr) bool {
+	return e.Routine != nil && e.Routine.IsBuiltin()
+}
+
+// overriddenWrite returns the source code for a write or writeln statement if the
+// builtin procedure is overridden, or an empty string otherwise.
+func (g *generator) overriddenWrite(stmt *parser.WriteStatement) string {
+	name := "write"
+	if stmt.AppendNewLine {
+		name = "writeln"
+	}
+
+	override, ok := g.BuiltinOverrides[name]
+	if !ok {
+		return ""
+	}
+
+	var params []parser.Expression
+	if stmt.FileVar != nil {
+		params = append(params, stmt.FileVar)
+	}
+	params = append(params, stmt.ActualParams...)
+
+	return g.emitOverride(override, &parser.ProcedureCallStatement{Name: name, ActualParams: params})
 }
 
-func getBuiltinConstant(ident string) (string, bool) {
+func (g *generator) getBuiltinConstant(ident string) (string, bool) {
 	switch ident {
 	case "maxint":
-		return "system.MaxInt", true
+		return g.system("MaxInt"), true
 	}
 	return "", false
 }
 
-func generateBuiltinProcedure(stmt *parser.ProcedureCallStatement) string {
+func (g *generator) generateBuiltinProcedure(stmt *parser.ProcedureCallStatement) string {
+	if override, ok := g.BuiltinOverrides[stmt.Name]; ok {
+		return g.emitOverride(override, stmt)
+	}
+
 	switch stmt.Name {
 	case "new":
 		typ := stmt.ActualParams[0].Type().(*parser.PointerType).Type_
-		typeName := typ.TypeName()
-		if typeName != "" {
-			if isBooleanType(typ) {
-				typeName = "bool"
-			}
-			return fmt.Sprintf("%s = new(%s)", toExpr(stmt.ActualParams[0]), typeName)
+		if typeName := typ.TypeName(); typeName != "" && !isPredeclaredGoType(typ) {
+			return fmt.Sprintf("%s = new(%s)", g.toExpr(stmt.ActualParams[0]), typeName)
 		}
-		return toExpr(stmt.ActualParams[0]) + " = new(" + toGoType(typ) + ")"
+		return g.toExpr(stmt.ActualParams[0]) + " = new(" + g.toGoType(typ) + ")"
 	case "dispose":
-		return toExpr(stmt.ActualParams[0]) + " = nil"
-	case "read":
-		return "system.Read" + toPointerParamList(stmt.ActualParams)
-	case "readln":
-		return "system.Readln" + toPointerParamList(stmt.ActualParams)
+		if g.CheckedPointers {
+			ptr := stmt.ActualParams[0].Type().(*parser.PointerType)
+			if ptr.TypeName() != "" {
+				// T can't be inferred from a named pointer type, so it is passed explicitly.
+				typ := g.toGoType(ptr.Type_)
+				return fmt.Sprintf("%s[%s]((**%s)(&%s))", g.system("Dispose"), typ, typ, g.toExpr(stmt.ActualParams[0]))
+			}
+			return g.system("Dispose") + "(&" + g.toExpr(stmt.ActualParams[0]) + ")"
+		}
+		return g.toExpr(stmt.ActualParams[0]) + " = nil"
+	case "read", "readln":
+		funcName := "Read"
+		if stmt.Name == "readln" {
+			funcName = "Readln"
+		}
+		params := stmt.ActualParams
+		var read string
+		if len(params) > 0 && isFile(params[0]) {
+			read = g.toExpr(params[0]) + "." + funcName
+			params = params[1:]
+		} else {
+			read = g.system(funcName)
+		}
+		return read + g.toPointerParamList(params) + g.rangeChecksAfterRead(params)
 	case "inc":
 		switch len(stmt.ActualParams) {
 		case 1:
-			return toExpr(stmt.ActualParams[0]) + "++"
+			return g.toExpr(stmt.ActualParams[0]) + "++"
 		case 2:
-			return toExpr(stmt.ActualParams[0]) + " += " + toExpr(stmt.ActualParams[1])
+			return g.toExpr(stmt.ActualParams[0]) + " += " + g.toExpr(stmt.ActualParams[1])
 		}
 	case "dec":
 		switch len(stmt.ActualParams) {
 		case 1:
-			return toExpr(stmt.ActualParams[0]) + "--"
+			return g.toExpr(stmt.ActualParams[0]) + "--"
 		case 2:
-			return toExpr(stmt.ActualParams[0]) + " -= " + toExpr(stmt.ActualParams[1])
+			return g.toExpr(stmt.ActualParams[0]) + " -= " + g.toExpr(stmt.ActualParams[1])
+		}
+	case "rewrite", "reset":
+		if v, ok := stmt.ActualParams[0].(*parser.VariableExpr); ok && v.VarDecl != nil && parser.IsStandardFile(v.VarDecl) {
+			return fmt.Sprintf("// %s(%s): the standard files are always open.", stmt.Name, v.Name)
+		}
+		return g.toExpr(stmt.ActualParams[0]) + "." + exportedName(stmt.Name) + "()"
+	case "assign":
+		return g.toExpr(stmt.ActualParams[0]) + ".Assign(" + g.toExpr(stmt.ActualParams[1]) + ")"
+	case "close":
+		return g.toExpr(stmt.ActualParams[0]) + ".Close()"
+	case "seek":
+		return g.toExpr(stmt.ActualParams[0]) + ".Seek(" + g.toExpr(stmt.ActualParams[1]) + ")"
+	case "page":
+		if len(stmt.ActualParams) == 0 {
+			return g.system("Page") + "()"
 		}
-	case "rewrite", "reset", "unpack", "pack", "get", "put":
-		return fmt.Sprintf("/* TODO: %s%s */", stmt.Name, actualParams(stmt.ActualParams, nil))
+		return g.toExpr(stmt.ActualParams[0]) + ".Page()"
+	case "exit":
+		return "return"
+	case "get", "put":
+		return g.toExpr(stmt.ActualParams[0]) + "." + exportedName(stmt.Name) + "()"
+	case "unpack", "pack":
+		return fmt.Sprintf("/* TODO: %s%s */", stmt.Name, g.actualParams(stmt.ActualParams, nil))
 	}
 	return "BUG: missing builtin procedure " + stmt.Name
 }
 
-func toPointerParamList(params []parser.Expression) string {
+func (g *generator) toPointerParamList(params []parser.Expression) string {
 	var buf strings.Builder
 
 	buf.WriteString("(")
@@ -746,8 +1540,13 @@ func toPointerParamList(params []parser.Expression) string {
 		if idx > 0 {
 			buf.WriteString(", ")
 		}
+		if baseType := readableSubrangeBase(param.Type()); baseType != "" && param.Type().TypeName() != "" {
+			// variables of named subrange types are of a named Go type, which the runtime can't read into.
+			buf.WriteString("(*" + baseType + ")(&" + g.toExpr(param) + ")")
+			continue
+		}
 		buf.WriteString("&")
-		buf.WriteString(toExpr(param))
+		buf.WriteString(g.toExpr(param))
 	}
 
 	buf.WriteString(")")
@@ -755,6 +1554,40 @@ func toPointerParamList(params []parser.Expression) string {
 	return buf.String()
 }
 
+// readableSubrangeBase returns the Go type that values of typ are read as if typ is a
+// subrange of integer or char, or an empty string otherwise.
+func readableSubrangeBase(typ parser.DataType) string {
+	st, ok := typ.(*parser.SubrangeType)
+	if !ok {
+		return ""
+	}
+	switch st.Type_.(type) {
+	case *parser.IntegerType:
+		return "int"
+	case *parser.CharType:
+		return "byte"
+	}
+	return ""
+}
+
+// rangeChecksAfterRead returns the range checks of the variables of subrange types
+// that values were read into, if range checks are enabled.
+func (g *generator) rangeChecksAfterRead(params []parser.Expression) string {
+	if !g.RangeChecks {
+		return ""
+	}
+
+	var buf strings.Builder
+	for _, param := range params {
+		if readableSubrangeBase(param.Type()) == "" {
+			continue
+		}
+		st := param.Type().(*parser.SubrangeType)
+		fmt.Fprintf(&buf, "\n%s(%s, %d, %d)", g.system("CheckRange"), g.toExpr(param), st.LowerBound, st.UpperBound)
+	}
+	return buf.String()
+}
+
 func isCharArray(typ parser.DataType) bool {
 	arrType, ok := typ.(*parser.ArrayType)
 	return ok && parser.IsCharType(arrType.ElementType)
@@ -765,6 +1598,19 @@ func isString(typ parser.DataType) bool {
 	return ok
 }
 
+// fitsStringLength returns true if the value of expr is known to be at most maxLength
+// characters long, so that it doesn't need to be truncated when it is assigned to a
+// string with that maximum length.
+func fitsStringLength(expr parser.Expression, maxLength int) bool {
+	if se, ok := expr.(*parser.StringExpr); ok {
+		return len(se.Value) <= maxLength
+	}
+	if st, ok := expr.Type().(*parser.StringType); ok {
+		return st.MaxLength > 0 && st.MaxLength <= maxLength
+	}
+	return true
+}
+
 func isStringish(typ parser.DataType) bool {
 	return isCharArray(typ) || isString(typ)
 }
@@ -774,27 +1620,47 @@ func isInteger(typ parser.DataType) bool {
 	return ok
 }
 
-func assignment(stmt *parser.AssignmentStatement) string {
+func isReal(typ parser.DataType) bool {
+	_, ok := typ.(*parser.RealType)
+	return ok
+}
+
+// isIntegerValued returns true if the type is integer or a subrange of integer.
+func isIntegerValued(typ parser.DataType) bool {
+	if st, ok := typ.(*parser.SubrangeType); ok {
+		return isInteger(st.Type_)
+	}
+	return isInteger(typ)
+}
+
+func (g *generator) assignment(stmt *parser.AssignmentStatement) string {
 	if isCharArray(stmt.LeftExpr.Type()) {
 		if isCharArray(stmt.RightExpr.Type()) {
-			return fmt.Sprintf("copy(%s[:], %s[:])", toExpr(stmt.LeftExpr), toExpr(stmt.RightExpr))
+			return fmt.Sprintf("copy(%s[:], %s[:])", g.toExpr(stmt.LeftExpr), g.toExpr(stmt.RightExpr))
 		}
 		if isString(stmt.RightExpr.Type()) {
-			return fmt.Sprintf("copy(%s[:], []byte(%s))", toExpr(stmt.LeftExpr), toExpr(stmt.RightExpr))
+			if _, isLiteral := stmt.RightExpr.(*parser.StringExpr); isLiteral {
+				return fmt.Sprintf("copy(%s[:], []byte(%s))", g.toExpr(stmt.LeftExpr), g.toExpr(stmt.RightExpr))
+			}
+			// strings may be shorter than the array, so the array is padded with spaces.
+			return fmt.Sprintf("%s(%s[:], %s)", g.system("CopyString"), g.toExpr(stmt.LeftExpr), g.toExpr(stmt.RightExpr))
 		}
 	} else if isString(stmt.LeftExpr.Type()) {
 		if isCharArray(stmt.RightExpr.Type()) {
-			return fmt.Sprintf("%s = string(%s[:])", toExpr(stmt.LeftExpr), toExpr(stmt.RightExpr))
+			return fmt.Sprintf("%s = string(%s[:])", g.toExpr(stmt.LeftExpr), g.toExpr(stmt.RightExpr))
+		}
+		if maxLength := stmt.LeftExpr.Type().(*parser.StringType).MaxLength; maxLength > 0 && !fitsStringLength(stmt.RightExpr, maxLength) {
+			return fmt.Sprintf("%s = %s(%s, %d)", g.toExpr(stmt.LeftExpr), g.system("TruncateString"), g.toExpr(stmt.RightExpr), maxLength)
 		}
 	} else if isSetType(stmt.LeftExpr.Type()) && isSetType(stmt.RightExpr.Type()) {
 		leftExpr := stmt.LeftExpr
 		ptrPrefix := "&"
 		if isBooleanType(leftExpr.Type().(*parser.SetType).ElementType) && isBooleanType(stmt.RightExpr.Type().(*parser.SetType).ElementType) {
-			return fmt.Sprintf("system.BoolSetAssign(%s%s, %s)", ptrPrefix, toExpr(leftExpr), toExpr(stmt.RightExpr))
+			return fmt.Sprintf("%s(%s%s, %s)", g.system("BoolSetAssign"), ptrPrefix, g.toExpr(leftExpr), g.toExpr(stmt.RightExpr))
 		} else if isBooleanType(leftExpr.Type().(*parser.SetType).ElementType) && !isBooleanType(stmt.RightExpr.Type().(*parser.SetType).ElementType) {
-			return fmt.Sprintf("system.SetAssignToBool(%s%s, %s)", ptrPrefix, toExpr(leftExpr), toExpr(stmt.RightExpr))
+			return fmt.Sprintf("%s(%s%s, %s)", g.system("SetAssignToBool"), ptrPrefix, g.toExpr(leftExpr), g.toExpr(stmt.RightExpr))
 		} else if !isBooleanType(leftExpr.Type().(*parser.SetType).ElementType) && isBooleanType(stmt.RightExpr.Type().(*parser.SetType).ElementType) {
-			return fmt.Sprintf("system.SetAssignFromBool(%s%s, %s)", ptrPrefix, toExpr(leftExpr), toExpr(stmt.RightExpr))
+			return fmt.Sprintf("%s(%s%s, %s)", g.system("SetAssignFromBool"), ptrPrefix, g.toExpr(leftExpr), g.toExpr(stmt.RightExpr))
 		}
 
 		if derefExpr, ok := leftExpr.(*parser.DerefExpr); ok {
@@ -802,29 +1668,50 @@ func assignment(stmt *parser.AssignmentStatement) string {
 			ptrPrefix = ""
 		}
 
-		return fmt.Sprintf("system.SetAssign(%s%s, %s)", ptrPrefix, toExpr(leftExpr), toExpr(stmt.RightExpr))
+		return fmt.Sprintf("%s(%s%s, %s)", g.system("SetAssign"), ptrPrefix, g.toExpr(leftExpr), g.toExpr(stmt.RightExpr))
+	}
+
+	if isEnumSubrangeOf(stmt.LeftExpr.Type(), stmt.RightExpr.Type()) || isEnumSubrangeOf(stmt.RightExpr.Type(), stmt.LeftExpr.Type()) {
+		// enum subranges are aliases of their enum type in Go, so no conversion is necessary.
+		return fmt.Sprintf("%s = %s", g.toExpr(stmt.LeftExpr), g.toExpr(stmt.RightExpr))
 	}
 
 	if !stmt.LeftExpr.Type().Equals(stmt.RightExpr.Type()) && stmt.LeftExpr.Type().IsCompatibleWith(stmt.RightExpr.Type(), true) && stmt.LeftExpr.Type().TypeName() != stmt.RightExpr.Type().TypeName() {
-		return fmt.Sprintf("%s = %s(%s)", toExpr(stmt.LeftExpr), toGoType(stmt.LeftExpr.Type()), toExpr(stmt.RightExpr))
+		return fmt.Sprintf("%s = %s(%s)", g.toExpr(stmt.LeftExpr), g.toGoType(stmt.LeftExpr.Type()), g.toExpr(stmt.RightExpr))
 	}
 
-	return fmt.Sprintf("%s = %s", toExpr(stmt.LeftExpr), toExpr(stmt.RightExpr))
+	return fmt.Sprintf("%s = %s", g.toExpr(stmt.LeftExpr), g.toExpr(stmt.RightExpr))
 }
 
 func isBooleanType(dt parser.DataType) bool {
 	return parser.IsBooleanType(dt)
 }
 
+// isElseIf returns true if the else branch of an if statement can be emitted
+// as an else if, i.e. it's an unlabeled if statement.
+func isElseIf(stmt parser.Statement) bool {
+	ifStmt, ok := stmt.(*parser.IfStatement)
+	return ok && ifStmt.Label() == nil
+}
+
+func isFile(expr parser.Expression) bool {
+	_, ok := expr.Type().(*parser.FileType)
+	return ok
+}
+
+func isTextFile(expr parser.Expression) bool {
+	return parser.IsTextType(expr.Type())
+}
+
 func isSetType(dt parser.DataType) bool {
 	_, ok := dt.(*parser.SetType)
 	return ok
 }
 
-func booleanForLoop(stmt *parser.ForStatement) string {
+func (g *generator) booleanForLoop(stmt *parser.ForStatement) string {
 	rangeFunc := "BoolRange"
 	if stmt.DownTo {
 		rangeFunc = "BoolRangeDown"
 	}
-	return fmt.Sprintf("for _, %s := range system.%s(%s, %s)", stmt.Name, rangeFunc, toExpr(stmt.InitialExpr), toExpr(stmt.FinalExpr))
+	return fmt.Sprintf("for _, %s := range %s(%s, %s)", stmt.Name, g.system(rangeFunc), g.toExpr(stmt.InitialExpr), g.toExpr(stmt.FinalExpr))
 }
@@ -1,6 +1,9 @@
 package system
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 func AbsInt(i int) int {
 	if i < 0 {
@@ -67,6 +70,28 @@ func Round(r float64) int {
 	return Trunc(r + 0.5)
 }
 
+// Mod implements the mod operator as defined by ISO Pascal, which
+// unlike Go's % operator never returns a negative result.
+func Mod(i, j int) int {
+	if j <= 0 {
+		panic(fmt.Errorf("mod: right operand %d is not positive", j))
+	}
+	r := i % j
+	if r < 0 {
+		r += j
+	}
+	return r
+}
+
+// Div implements the div operator as defined by ISO Pascal, which truncates
+// the quotient towards zero.
+func Div(i, j int) int {
+	if j == 0 {
+		panic(fmt.Errorf("div: division by zero"))
+	}
+	return i / j
+}
+
 func Chr(i int) byte {
 	return byte(i)
 }
@@ -2,4 +2,12 @@ package system
 
 import "math"
 
-const MaxInt = math.MaxInt
+// Pascal's integer type is translated to Go's int, so the range of integer values
+// is that of int.
+const (
+	// MaxInt is the largest integer value, and the value of Pascal's maxint.
+	MaxInt = math.MaxInt
+
+	// MinInt is the smallest integer value.
+	MinInt = math.MinInt
+)
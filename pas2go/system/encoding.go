@@ -0,0 +1,96 @@
+package system
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// EncodedSize returns the number of bytes that a value of type T occupies when it
+// is stored as an element of a file. Only types of a fixed size can be stored in files.
+func EncodedSize[T any]() int {
+	var v T
+	return encodedSize(reflect.TypeOf(&v).Elem())
+}
+
+func encodedSize(t reflect.Type) int {
+	switch t.Kind() {
+	case reflect.Int, reflect.Float64:
+		return 8
+	case reflect.Uint8, reflect.Bool:
+		return 1
+	case reflect.Array:
+		return t.Len() * encodedSize(t.Elem())
+	case reflect.Struct:
+		size := 0
+		for i := 0; i < t.NumField(); i++ {
+			size += encodedSize(t.Field(i).Type)
+		}
+		return size
+	}
+	panic(fmt.Errorf("type %s can't be stored in a file", t))
+}
+
+func encodeValue(buf []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Int:
+		return appendUint64(buf, uint64(v.Int()))
+	case reflect.Float64:
+		return appendUint64(buf, math.Float64bits(v.Float()))
+	case reflect.Uint8:
+		return append(buf, byte(v.Uint()))
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			buf = encodeValue(buf, v.Index(i))
+		}
+		return buf
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			buf = encodeValue(buf, v.Field(i))
+		}
+		return buf
+	}
+	panic(fmt.Errorf("type %s can't be stored in a file", v.Type()))
+}
+
+func appendUint64(buf []byte, i uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], i)
+	return append(buf, b[:]...)
+}
+
+// decodeValue decodes buf into v, which must be settable, and returns the remaining
+// bytes. The fields of records are settable, as the transpiler exports them.
+func decodeValue(buf []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Int:
+		v.SetInt(int64(binary.LittleEndian.Uint64(buf)))
+		return buf[8:]
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(buf)))
+		return buf[8:]
+	case reflect.Uint8:
+		v.SetUint(uint64(buf[0]))
+		return buf[1:]
+	case reflect.Bool:
+		v.SetBool(buf[0] != 0)
+		return buf[1:]
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			buf = decodeValue(buf, v.Index(i))
+		}
+		return buf
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			buf = decodeValue(buf, v.Field(i))
+		}
+		return buf
+	}
+	panic(fmt.Errorf("type %s can't be stored in a file", v.Type()))
+}
@@ -1,5 +1,182 @@
 package system
 
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// FileType is a file of elements of type T. All elements are stored with the same
+// fixed size, which allows random access to them. A file that isn't bound to a file
+// name is backed by a temporary file.
+//
+// The buffer variable holds the element at the current position of the file after
+// Reset and Get, and is written to the file by Put.
 type FileType[T any] struct {
-	// TODO: implement
+	name   string
+	file   *os.File
+	buffer T
+}
+
+// Assign binds the file to the provided file name.
+func (f *FileType[T]) Assign(name string) {
+	f.name = name
+}
+
+// Close closes the file.
+func (f *FileType[T]) Close() {
+	if f.file == nil {
+		panic(fmt.Errorf("close: file is not open"))
+	}
+	f.file.Close()
+	f.file = nil
+}
+
+// Rewrite truncates the file and prepares it for writing.
+func (f *FileType[T]) Rewrite() {
+	if f.name == "" && f.file != nil {
+		if err := f.file.Truncate(0); err != nil {
+			panic(fmt.Errorf("rewrite: %w", err))
+		}
+		f.seek("rewrite", 0)
+		return
+	}
+
+	if f.file != nil {
+		f.file.Close()
+	}
+
+	var err error
+	if f.name == "" {
+		f.file, err = os.CreateTemp("", "pas2go")
+		if err == nil {
+			// the temporary file remains accessible through the open file handle.
+			os.Remove(f.file.Name())
+		}
+	} else {
+		f.file, err = os.Create(f.name)
+	}
+	if err != nil {
+		panic(fmt.Errorf("rewrite: %w", err))
+	}
+}
+
+// Reset prepares the file for reading from its beginning.
+func (f *FileType[T]) Reset() {
+	if f.file == nil {
+		if f.name == "" {
+			panic(fmt.Errorf("reset: file is neither bound to a name nor was it written before"))
+		}
+
+		var err error
+		f.file, err = os.OpenFile(f.name, os.O_RDWR, 0)
+		if err != nil {
+			panic(fmt.Errorf("reset: %w", err))
+		}
+	}
+
+	f.Seek(0)
+}
+
+// Buffer returns a pointer to the buffer variable of the file.
+func (f *FileType[T]) Buffer() *T {
+	return &f.buffer
+}
+
+// Get advances the file to the next element, and loads it into the buffer variable.
+func (f *FileType[T]) Get() {
+	f.seek("get", f.FilePos()+1)
+	f.load()
+}
+
+// Put writes the buffer variable to the file at its current position, and advances
+// the file to the next element.
+func (f *FileType[T]) Put() {
+	buf := encodeValue(nil, reflect.ValueOf(&f.buffer).Elem())
+	if _, err := f.handle("put").Write(buf); err != nil {
+		panic(fmt.Errorf("put: %w", err))
+	}
+}
+
+// load reads the element at the current position into the buffer variable without
+// advancing the file. At the end of the file, the buffer variable is left unchanged.
+func (f *FileType[T]) load() {
+	if f.Eof() {
+		return
+	}
+	buf := make([]byte, EncodedSize[T]())
+	if _, err := io.ReadFull(f.handle("get"), buf); err != nil {
+		panic(fmt.Errorf("get: %w", err))
+	}
+	decodeValue(buf, reflect.ValueOf(&f.buffer).Elem())
+	f.seek("get", f.FilePos()-1)
+}
+
+// Read reads the next elements from the file into the provided variables. Like in
+// ISO Pascal, reading an element assigns the buffer variable to it and then calls Get,
+// so that the buffer variable holds the element after the one that was read.
+func (f *FileType[T]) Read(a ...*T) {
+	for _, v := range a {
+		if f.Eof() {
+			panic(fmt.Errorf("read: %w", io.ErrUnexpectedEOF))
+		}
+		*v = f.buffer
+		f.Get()
+	}
+}
+
+// Write writes the provided elements to the file. Like in ISO Pascal, writing an
+// element assigns it to the buffer variable and then calls Put.
+func (f *FileType[T]) Write(a ...T) {
+	for _, v := range a {
+		f.buffer = v
+		f.Put()
+	}
+}
+
+// Eof returns true if the file is positioned after its last element.
+func (f *FileType[T]) Eof() bool {
+	return f.file == nil || f.FilePos() >= f.FileSize()
+}
+
+// Seek positions the file at the element with the provided index, and loads it into
+// the buffer variable. The first element has index 0.
+func (f *FileType[T]) Seek(n int) {
+	f.seek("seek", n)
+	f.load()
+}
+
+func (f *FileType[T]) seek(op string, n int) {
+	if n < 0 {
+		panic(fmt.Errorf("%s: invalid position %d", op, n))
+	}
+	if _, err := f.handle(op).Seek(int64(n*EncodedSize[T]()), io.SeekStart); err != nil {
+		panic(fmt.Errorf("%s: %w", op, err))
+	}
+}
+
+// FilePos returns the index of the element at which the file is currently positioned.
+func (f *FileType[T]) FilePos() int {
+	pos, err := f.handle("filepos").Seek(0, io.SeekCurrent)
+	if err != nil {
+		panic(fmt.Errorf("filepos: %w", err))
+	}
+	return int(pos) / EncodedSize[T]()
+}
+
+// FileSize returns the number of elements in the file.
+func (f *FileType[T]) FileSize() int {
+	fi, err := f.handle("filesize").Stat()
+	if err != nil {
+		panic(fmt.Errorf("filesize: %w", err))
+	}
+	return int(fi.Size()) / EncodedSize[T]()
+}
+
+func (f *FileType[T]) handle(op string) *os.File {
+	if f.file == nil {
+		panic(fmt.Errorf("%s: file is not open", op))
+	}
+	return f.file
 }
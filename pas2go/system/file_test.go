@@ -0,0 +1,77 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testRecord is a record type like the transpiler generates it, with exported fields.
+type testRecord struct {
+	Id      int
+	Height  float64
+	Initial byte
+	Valid   bool
+	Scores  [3]int
+}
+
+func TestFileTypeSeek(t *testing.T) {
+	require.Equal(t, 8+8+1+1+3*8, EncodedSize[testRecord]())
+
+	var f FileType[testRecord]
+	f.Assign(filepath.Join(t.TempDir(), "records.dat"))
+	f.Rewrite()
+	for i := 0; i < 5; i++ {
+		f.Write(testRecord{Id: i, Height: float64(i) / 2, Initial: byte('a' + i), Valid: i%2 == 0, Scores: [3]int{i, -i, i * i}})
+	}
+	require.Equal(t, 5, f.FileSize())
+	require.Equal(t, 5, f.FilePos())
+	f.Close()
+
+	f.Reset()
+	f.Seek(3)
+	var r testRecord
+	f.Read(&r)
+	require.Equal(t, testRecord{Id: 3, Height: 1.5, Initial: 'd', Valid: false, Scores: [3]int{3, -3, 9}}, r)
+	require.Equal(t, 4, f.FilePos())
+	require.False(t, f.Eof())
+
+	f.Seek(4)
+	f.Read(&r)
+	require.Equal(t, 4, r.Id)
+	require.True(t, f.Eof())
+	f.Close()
+}
+
+func TestFileTypeBuffer(t *testing.T) {
+	var f FileType[int]
+	f.Rewrite()
+	for i := 1; i <= 3; i++ {
+		*f.Buffer() = i * 10
+		f.Put()
+	}
+	require.Equal(t, 3, f.FileSize())
+
+	f.Reset()
+	var values []int
+	for !f.Eof() {
+		values = append(values, *f.Buffer())
+		f.Get()
+	}
+	require.Equal(t, []int{10, 20, 30}, values)
+
+	f.Reset()
+	var i int
+	f.Read(&i)
+	require.Equal(t, 10, i)
+	require.Equal(t, 20, *f.Buffer())
+	f.Read(&i)
+	require.Equal(t, 20, i)
+	require.Equal(t, 30, *f.Buffer())
+
+	f.Write(50)
+	require.Equal(t, 50, *f.Buffer())
+	require.True(t, f.Eof())
+	f.Close()
+}
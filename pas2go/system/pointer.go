@@ -0,0 +1,39 @@
+package system
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// disposedPointers holds, for each pointer target type, the sentinel pointer
+// that disposed pointers of that type are set to.
+var disposedPointers = map[reflect.Type]any{}
+
+func disposedPointer[T any]() *T {
+	typ := reflect.TypeOf((*T)(nil))
+	p, ok := disposedPointers[typ]
+	if !ok {
+		p = new(T)
+		disposedPointers[typ] = p
+	}
+	return p.(*T)
+}
+
+// Dispose sets the pointer to a sentinel that marks it as disposed.
+func Dispose[T any](p **T) {
+	if *p == nil {
+		panic(fmt.Errorf("dispose: pointer is nil"))
+	}
+	if *p == disposedPointer[T]() {
+		panic(fmt.Errorf("dispose: pointer has already been disposed"))
+	}
+	*p = disposedPointer[T]()
+}
+
+// Deref returns the provided pointer, and panics if the pointer has been disposed.
+func Deref[T any](p *T) *T {
+	if p != nil && p == disposedPointer[T]() {
+		panic(fmt.Errorf("access to disposed pointer"))
+	}
+	return p
+}
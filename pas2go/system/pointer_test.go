@@ -0,0 +1,29 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisposeDeref(t *testing.T) {
+	p := new(int)
+	*Deref(p) = 23
+	require.Equal(t, 23, *Deref(p))
+
+	q := new(bool)
+	Dispose(&q)
+
+	Dispose(&p)
+	require.PanicsWithError(t, "access to disposed pointer", func() {
+		*Deref(p) = 42
+	})
+	require.PanicsWithError(t, "dispose: pointer has already been disposed", func() {
+		Dispose(&p)
+	})
+
+	p = new(int)
+	require.NotPanics(t, func() {
+		*Deref(p) = 42
+	})
+}
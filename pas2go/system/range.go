@@ -0,0 +1,10 @@
+package system
+
+import "fmt"
+
+// CheckRange panics with a range error if v is not within lower..upper.
+func CheckRange[T ~int | ~byte](v, lower, upper T) {
+	if v < lower || v > upper {
+		panic(fmt.Errorf("range error: %d is not within %d..%d", v, lower, upper))
+	}
+}
@@ -0,0 +1,27 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRange(t *testing.T) {
+	type digit int
+
+	require.NotPanics(t, func() {
+		CheckRange(1, 1, 10)
+		CheckRange(10, 1, 10)
+		CheckRange[digit](7, 0, 9)
+		CheckRange[byte]('m', 'a', 'z')
+	})
+	require.PanicsWithError(t, "range error: 11 is not within 1..10", func() {
+		CheckRange(11, 1, 10)
+	})
+	require.PanicsWithError(t, "range error: -1 is not within 0..9", func() {
+		CheckRange[digit](-1, 0, 9)
+	})
+	require.PanicsWithError(t, "range error: 65 is not within 97..122", func() {
+		CheckRange[byte]('A', 'a', 'z')
+	})
+}
@@ -1,13 +1,257 @@
 package system
 
-import "fmt"
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
 
-// TODO: improve this and make I/O closer to Pascal.
+var input = bufio.NewReader(os.Stdin)
 
+// Read reads values from standard input into the provided variables.
 func Read(a ...any) {
-	fmt.Scanln(a...)
+	for _, v := range a {
+		readValue(input, v)
+	}
 }
 
+// Readln reads values from standard input into the provided variables, and then
+// skips the remainder of the current line.
 func Readln(a ...any) {
-	fmt.Scanln(a...)
+	Read(a...)
+	skipLine(input)
+}
+
+func readValue(r *bufio.Reader, v any) {
+	switch p := v.(type) {
+	case *string:
+		line, err := r.ReadString('\n')
+		if err == nil {
+			r.UnreadByte()
+		} else if err != io.EOF {
+			panic(fmt.Errorf("read: %w", err))
+		}
+		*p = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	case *byte:
+		b, err := r.ReadByte()
+		if err != nil {
+			panic(fmt.Errorf("read: %w", err))
+		}
+		if b == '\n' {
+			b = ' '
+		}
+		*p = b
+	case *int:
+		i, err := readInt(r)
+		if err != nil {
+			panic(fmt.Errorf("read: %w", err))
+		}
+		*p = i
+	case *float64:
+		f, err := readReal(r)
+		if err != nil {
+			panic(fmt.Errorf("read: %w", err))
+		}
+		*p = f
+	case *bool:
+		b, err := readBool(r)
+		if err != nil {
+			panic(fmt.Errorf("read: %w", err))
+		}
+		*p = b
+	default:
+		panic(fmt.Errorf("read: can't read into %T", v))
+	}
+}
+
+// readInt skips leading blanks and line ends, and then reads an optionally signed
+// integer. The character following the integer is left unread.
+func readInt(r io.RuneScanner) (int, error) {
+	c, err := skipSpace(r)
+	if err != nil {
+		return 0, err
+	}
+
+	negative := false
+	if c == '+' || c == '-' {
+		negative = c == '-'
+		if c, _, err = r.ReadRune(); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+	}
+
+	if c < '0' || c > '9' {
+		r.UnreadRune()
+		return 0, fmt.Errorf("expected digit, got %q", c)
+	}
+
+	n := 0
+	for c >= '0' && c <= '9' {
+		n = n*10 + int(c-'0')
+		if c, _, err = r.ReadRune(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return 0, err
+		}
+	}
+	if err == nil {
+		r.UnreadRune()
+	}
+
+	if negative {
+		n = -n
+	}
+	return n, nil
+}
+
+// readReal skips leading blanks and line ends, and then reads a real number using
+// Pascal's syntax:
+//
+//	[ sign ] digit-sequence [ "." digit-sequence ] [ "e" [ sign ] digit-sequence ]
+//
+// The character following the number is left unread. If it continues the number,
+// e.g. a second decimal point, the number is malformed.
+func readReal(r io.RuneScanner) (float64, error) {
+	if _, err := skipSpace(r); err != nil {
+		return 0, err
+	}
+	r.UnreadRune()
+
+	var buf strings.Builder
+
+	acceptSign := func() error {
+		c, _, err := r.ReadRune()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if c == '+' || c == '-' {
+			buf.WriteRune(c)
+		} else {
+			r.UnreadRune()
+		}
+		return nil
+	}
+
+	acceptDigits := func() error {
+		n := 0
+		for {
+			c, _, err := r.ReadRune()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			if c < '0' || c > '9' {
+				r.UnreadRune()
+				break
+			}
+			buf.WriteRune(c)
+			n++
+		}
+		if n == 0 {
+			return fmt.Errorf("malformed real number %q: expected digit", buf.String())
+		}
+		return nil
+	}
+
+	// accept returns true if the next rune is one of valid, and consumes it if so.
+	accept := func(valid string) bool {
+		c, _, err := r.ReadRune()
+		if err != nil {
+			return false
+		}
+		if !strings.ContainsRune(valid, c) {
+			r.UnreadRune()
+			return false
+		}
+		buf.WriteRune(c)
+		return true
+	}
+
+	if err := acceptSign(); err != nil {
+		return 0, err
+	}
+	if err := acceptDigits(); err != nil {
+		return 0, err
+	}
+	if accept(".") {
+		if err := acceptDigits(); err != nil {
+			return 0, err
+		}
+	}
+	if accept("eE") {
+		if err := acceptSign(); err != nil {
+			return 0, err
+		}
+		if err := acceptDigits(); err != nil {
+			return 0, err
+		}
+	}
+	if accept(".eE") {
+		return 0, fmt.Errorf("malformed real number %q", buf.String())
+	}
+
+	return strconv.ParseFloat(buf.String(), 64)
+}
+
+// readBool skips leading blanks and line ends, and then reads a boolean value, which
+// is either true or false, regardless of case. The character following the value is
+// left unread.
+func readBool(r io.RuneScanner) (bool, error) {
+	c, err := skipSpace(r)
+	if err != nil {
+		return false, err
+	}
+
+	var buf strings.Builder
+	for unicode.IsLetter(c) {
+		buf.WriteRune(c)
+		if c, _, err = r.ReadRune(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return false, err
+		}
+	}
+	if err == nil {
+		r.UnreadRune()
+	}
+
+	switch word := buf.String(); strings.ToLower(word) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("expected true or false, got %q", word)
+	}
+}
+
+// skipSpace skips whitespace and returns the first rune that isn't whitespace.
+func skipSpace(r io.RuneScanner) (rune, error) {
+	for {
+		c, _, err := r.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(c) {
+			return c, nil
+		}
+	}
+}
+
+func skipLine(r *bufio.Reader) {
+	if _, err := r.ReadString('\n'); err != nil && err != io.EOF {
+		panic(fmt.Errorf("readln: %w", err))
+	}
 }
@@ -0,0 +1,197 @@
+package system
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadInteger(t *testing.T) {
+	testData := []struct {
+		input    string
+		expected int
+		rest     string
+	}{
+		{"  42x", 42, "x"},
+		{"\n\t 7\n", 7, "\n"},
+		{"-13 14", -13, " 14"},
+		{"+5", 5, ""},
+		{"0012;", 12, ";"},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.input, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.input))
+
+			var i int
+			readValue(r, &i)
+			require.Equal(t, tt.expected, i)
+
+			rest, _ := r.ReadString(0)
+			require.Equal(t, tt.rest, rest)
+		})
+	}
+}
+
+func TestReadIntegerInvalid(t *testing.T) {
+	for _, input := range []string{"", "   ", "x42", "- 1", "-"} {
+		t.Run(input, func(t *testing.T) {
+			var i int
+			require.Panics(t, func() {
+				readValue(bufio.NewReader(strings.NewReader(input)), &i)
+			})
+		})
+	}
+}
+
+func TestTextFileReadInteger(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "numbers.txt")
+	require.NoError(t, os.WriteFile(fileName, []byte("  42x\n  \n 7 8\n"), 0644))
+
+	var (
+		f    TextFile
+		i, j int
+		c    byte
+	)
+	f.Assign(fileName)
+	f.Reset()
+
+	f.Read(&i, &c)
+	require.Equal(t, 42, i)
+	require.Equal(t, byte('x'), c)
+
+	f.Read(&i)
+	f.Readln(&j)
+	require.Equal(t, 7, i)
+	require.Equal(t, 8, j)
+	require.True(t, f.Eof())
+	f.Close()
+}
+
+func TestReadReal(t *testing.T) {
+	testData := []struct {
+		input    string
+		expected float64
+		rest     string
+	}{
+		{"3.14", 3.14, ""},
+		{"-2e3", -2000, ""},
+		{"  \n+1.5E-2 x", 0.015, " x"},
+		{"42;", 42, ";"},
+		{"7.25\n", 7.25, "\n"},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.input, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.input))
+
+			var f float64
+			readValue(r, &f)
+			require.Equal(t, tt.expected, f)
+
+			rest, _ := r.ReadString(0)
+			require.Equal(t, tt.rest, rest)
+		})
+	}
+}
+
+func TestReadRealMalformed(t *testing.T) {
+	var f float64
+	require.PanicsWithError(t, `read: malformed real number "1.2."`, func() {
+		readValue(bufio.NewReader(strings.NewReader("1.2.3")), &f)
+	})
+
+	for _, input := range []string{"1.2.3", "1.", ".5", "2e", "3e+", "-", "x", ""} {
+		t.Run(input, func(t *testing.T) {
+			require.Panics(t, func() {
+				readValue(bufio.NewReader(strings.NewReader(input)), &f)
+			})
+		})
+	}
+}
+
+func TestReadBoolean(t *testing.T) {
+	testData := []struct {
+		input    string
+		expected bool
+		rest     string
+	}{
+		{"TRUE", true, ""},
+		{"false", false, ""},
+		{"  \nTrue x", true, " x"},
+		{"FaLsE;", false, ";"},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.input, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.input))
+
+			b := !tt.expected
+			readValue(r, &b)
+			require.Equal(t, tt.expected, b)
+
+			rest, _ := r.ReadString(0)
+			require.Equal(t, tt.rest, rest)
+		})
+	}
+}
+
+func TestReadBooleanInvalid(t *testing.T) {
+	var b bool
+	require.PanicsWithError(t, `read: expected true or false, got "yes"`, func() {
+		readValue(bufio.NewReader(strings.NewReader("yes")), &b)
+	})
+
+	for _, input := range []string{"", "  ", "1", "truest", "t"} {
+		t.Run(input, func(t *testing.T) {
+			require.Panics(t, func() {
+				readValue(bufio.NewReader(strings.NewReader(input)), &b)
+			})
+		})
+	}
+}
+
+func TestTextFileReadBoolean(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "booleans.txt")
+	require.NoError(t, os.WriteFile(fileName, []byte("TRUE false\nrest\nFalse\n"), 0644))
+
+	var (
+		f       TextFile
+		a, b, c bool
+	)
+	f.Assign(fileName)
+	f.Reset()
+
+	f.Read(&a)
+	f.Readln(&b)
+	f.Readln()
+	c = true
+	f.Readln(&c)
+	require.True(t, a)
+	require.False(t, b)
+	require.False(t, c)
+	require.True(t, f.Eof())
+	f.Close()
+}
+
+func TestReadlnSkipsLine(t *testing.T) {
+	defer func(r *bufio.Reader) { input = r }(input)
+
+	input = bufio.NewReader(strings.NewReader("header line\n\n42 43\n44"))
+
+	var i, j int
+	Readln()
+	Readln()
+	Readln(&i)
+	Readln(&j)
+	require.Equal(t, 42, i)
+	require.Equal(t, 44, j)
+
+	// skipping the line at the end of the input is a no-op.
+	Readln()
+	Readln()
+}
@@ -3,14 +3,16 @@ package system
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 type setTypeConstraint interface {
-	byte | ~int | bool
+	~byte | ~int | bool
 }
 
 type intSetTypeConstraint interface {
-	byte | ~int
+	~byte | ~int
 }
 
 type SetType[T setTypeConstraint] struct {
@@ -26,6 +28,45 @@ func (ts SetType[T]) In(elem T) bool {
 	return false
 }
 
+// String returns the elements of the set in ascending order, e.g. [1,2,3]. Chars
+// are written as characters. This is meant for debugging.
+func (ts SetType[T]) String() string {
+	seen := make(map[T]struct{})
+	var values []T
+	for _, v := range ts.values {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			values = append(values, v)
+		}
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return setOrd(values[i]) < setOrd(values[j])
+	})
+
+	elems := make([]string, 0, len(values))
+	for _, v := range values {
+		if b, isByte := any(v).(byte); isByte {
+			elems = append(elems, string(rune(b)))
+		} else {
+			elems = append(elems, fmt.Sprint(v))
+		}
+	}
+
+	return "[" + strings.Join(elems, ",") + "]"
+}
+
+// setOrd returns the ordinal value of a set element.
+func setOrd(v any) int {
+	switch vv := v.(type) {
+	case bool:
+		return BoolOrd(vv)
+	case byte:
+		return int(vv)
+	}
+	return int(reflect.ValueOf(v).Int())
+}
+
 func (ts SetType[T]) Union(o SetType[T]) SetType[T] {
 	set := make(map[T]struct{})
 
@@ -64,6 +105,35 @@ func (ts SetType[T]) Difference(o SetType[T]) SetType[T] {
 	return newSet
 }
 
+// SymmetricDifference returns the set of elements that are in exactly one of the two sets.
+func (ts SetType[T]) SymmetricDifference(o SetType[T]) SetType[T] {
+	set := make(map[T]struct{})
+
+	for _, v := range ts.values {
+		set[v] = struct{}{}
+	}
+
+	inBoth := make(map[T]struct{})
+	for _, v := range o.values {
+		if _, ok := set[v]; ok {
+			inBoth[v] = struct{}{}
+		} else {
+			set[v] = struct{}{}
+		}
+	}
+	for v := range inBoth {
+		delete(set, v)
+	}
+
+	newSet := SetType[T]{}
+
+	for k := range set {
+		newSet.values = append(newSet.values, k)
+	}
+
+	return newSet
+}
+
 func (ts SetType[T]) Intersection(o SetType[T]) SetType[T] {
 	set := make(map[T]struct{})
 
@@ -99,13 +169,34 @@ func Set[T setTypeConstraint](values ...any) SetType[T] {
 		case []T:
 			set.values = append(set.values, vv...)
 		default:
-			var foo T
-			panic(fmt.Errorf("can't construct set[%T] from %T", foo, v))
+			set.values = append(set.values, convertSetValues[T](v)...)
 		}
 	}
 	return set
 }
 
+// convertSetValues converts a value or a slice of values of a different but
+// convertible type, e.g. untyped integer constants for a set of a subrange type.
+func convertSetValues[T setTypeConstraint](v any) []T {
+	var foo T
+	elemType := reflect.TypeOf(foo)
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice {
+		values := make([]T, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, convertSetValues[T](rv.Index(i).Interface())...)
+		}
+		return values
+	}
+
+	if rv.Kind() == reflect.Bool || !rv.CanConvert(elemType) || elemType.Kind() == reflect.Bool {
+		panic(fmt.Errorf("can't construct set[%T] from %T", foo, v))
+	}
+
+	return []T{rv.Convert(elemType).Interface().(T)}
+}
+
 func (ts SetType[T]) Equals(os SetType[T]) bool {
 	// this is not very efficient.
 	for _, v := range ts.values {
@@ -127,24 +218,29 @@ func (ts SetType[T]) NotEquals(os SetType[T]) bool {
 	return !ts.Equals(os)
 }
 
+// Less returns true if the set is a proper subset of os.
 func (ts SetType[T]) Less(os SetType[T]) bool {
-	// TODO: implement
-	return false
+	return ts.LessEqual(os) && !os.LessEqual(ts)
 }
 
+// LessEqual returns true if the set is a subset of os.
 func (ts SetType[T]) LessEqual(os SetType[T]) bool {
-	// TODO: implement
-	return false
+	for _, v := range ts.values {
+		if !os.In(v) {
+			return false
+		}
+	}
+	return true
 }
 
+// Greater returns true if the set is a proper superset of os.
 func (ts SetType[T]) Greater(os SetType[T]) bool {
-	// TODO: implement
-	return false
+	return os.Less(ts)
 }
 
+// GreaterEqual returns true if the set is a superset of os.
 func (ts SetType[T]) GreaterEqual(os SetType[T]) bool {
-	// TODO: implement
-	return false
+	return os.LessEqual(ts)
 }
 
 func SetAssign[T1, T2 intSetTypeConstraint](to *SetType[T1], from SetType[T2]) {
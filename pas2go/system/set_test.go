@@ -0,0 +1,86 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetConvertsValues(t *testing.T) {
+	type small int
+
+	s := Set[small](1, Range(3, 4), small(6))
+	for _, v := range []small{1, 3, 4, 6} {
+		require.True(t, s.In(v), "%d is not in set", v)
+	}
+	require.False(t, s.In(2))
+
+	var u SetType[int]
+	SetAssign(&u, s)
+	require.True(t, u.Equals(Set[int](1, 3, 4, 6)))
+
+	require.Panics(t, func() { Set[small](true) })
+}
+
+func TestSetAssignBool(t *testing.T) {
+	var b SetType[bool]
+	SetAssignToBool(&b, Set[int](0, 1))
+	require.True(t, b.Equals(Set[bool](false, true)))
+
+	var i SetType[int]
+	SetAssignFromBool(&i, Set[bool](true))
+	require.True(t, i.Equals(Set[int](1)))
+}
+
+func TestSetSymmetricDifference(t *testing.T) {
+	testData := []struct {
+		name     string
+		a, b     SetType[int]
+		expected SetType[int]
+	}{
+		{"disjoint", Set[int](1, 2), Set[int](3, 4), Set[int](1, 2, 3, 4)},
+		{"overlapping", Set[int](1, 2, 3), Set[int](2, 3, 4), Set[int](1, 4)},
+		{"identical", Set[int](1, 2, 3), Set[int](1, 2, 3), Set[int]()},
+		{"empty", Set[int](), Set[int](5), Set[int](5)},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			require.True(t, tt.a.SymmetricDifference(tt.b).Equals(tt.expected))
+			require.True(t, tt.b.SymmetricDifference(tt.a).Equals(tt.expected))
+		})
+	}
+}
+
+func TestSetSubsetComparisons(t *testing.T) {
+	testData := []struct {
+		name                                   string
+		a, b                                   SetType[int]
+		less, lessEqual, greater, greaterEqual bool
+	}{
+		{"proper subset", Set[int](1, 2), Set[int](1, 2, 3), true, true, false, false},
+		{"proper superset", Set[int](1, 2, 3), Set[int](2), false, false, true, true},
+		{"identical", Set[int](1, 2), Set[int](2, 1), false, true, false, true},
+		{"disjoint", Set[int](1), Set[int](2), false, false, false, false},
+		{"empty", Set[int](), Set[int](4), true, true, false, false},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.less, tt.a.Less(tt.b))
+			require.Equal(t, tt.lessEqual, tt.a.LessEqual(tt.b))
+			require.Equal(t, tt.greater, tt.a.Greater(tt.b))
+			require.Equal(t, tt.greaterEqual, tt.a.GreaterEqual(tt.b))
+		})
+	}
+}
+
+func TestSetString(t *testing.T) {
+	type small int
+
+	require.Equal(t, "[]", Set[int]().String())
+	require.Equal(t, "[1,3,4,6]", Set[int](6, Range(3, 4), 1, 3).String())
+	require.Equal(t, "[-2,5]", Set[small](5, -2).String())
+	require.Equal(t, "[a,b,c]", Set[byte](byte('c'), byte('a'), byte('b')).String())
+	require.Equal(t, "[false,true]", Set[bool](true, false).String())
+}
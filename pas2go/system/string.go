@@ -0,0 +1,19 @@
+package system
+
+// CopyString copies s into the char array dst. If s is shorter than dst, the
+// remainder of dst is padded with spaces. If s is longer, it is truncated.
+func CopyString(dst []byte, s string) {
+	n := copy(dst, s)
+	for i := n; i < len(dst); i++ {
+		dst[i] = ' '
+	}
+}
+
+// TruncateString returns s truncated to at most maxLength characters, as is
+// required when s is assigned to a string with a maximum length.
+func TruncateString(s string, maxLength int) string {
+	if len(s) > maxLength {
+		return s[:maxLength]
+	}
+	return s
+}
@@ -0,0 +1,208 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// TextFile is a file of characters that is structured into lines. A text file that
+// isn't bound to a file name is backed by a temporary file.
+type TextFile struct {
+	name       string
+	file       *os.File
+	r          *bufio.Reader
+	w          *bufio.Writer
+	unbuffered bool // if true, everything written is flushed immediately.
+	standard   bool // if true, the file is the standard input or output, which is always open.
+	buffer     byte
+}
+
+// Input is the standard input as text file. It shares its buffer with Read and Readln.
+var Input = TextFile{file: os.Stdin, r: input, standard: true}
+
+// Output is the standard output as text file. Everything written to it is flushed
+// immediately, so that it appears in order with what is written by Write and Writeln.
+var Output = TextFile{file: os.Stdout, w: bufio.NewWriter(os.Stdout), unbuffered: true, standard: true}
+
+// Assign binds the file to the provided file name.
+func (f *TextFile) Assign(name string) {
+	f.name = name
+}
+
+// Close closes the file. Any data that has been written to it is flushed first.
+func (f *TextFile) Close() {
+	if f.file == nil {
+		panic(fmt.Errorf("close: file is not open"))
+	}
+	f.close()
+}
+
+// Rewrite truncates the file and prepares it for writing. As the standard output is
+// always open for writing, rewriting it only flushes it.
+func (f *TextFile) Rewrite() {
+	if f.standard && f.w != nil {
+		f.flush()
+		return
+	}
+
+	if f.name == "" && f.file != nil {
+		if err := f.file.Truncate(0); err != nil {
+			panic(fmt.Errorf("rewrite: %w", err))
+		}
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			panic(fmt.Errorf("rewrite: %w", err))
+		}
+	} else {
+		f.close()
+
+		var err error
+		if f.name == "" {
+			f.file, err = os.CreateTemp("", "pas2go")
+			if err == nil {
+				// the temporary file remains accessible through the open file handle.
+				os.Remove(f.file.Name())
+			}
+		} else {
+			f.file, err = os.Create(f.name)
+		}
+		if err != nil {
+			panic(fmt.Errorf("rewrite: %w", err))
+		}
+	}
+
+	f.r = nil
+	f.w = bufio.NewWriter(f.file)
+}
+
+// Reset prepares the file for reading from its beginning. As the standard input is
+// always open for reading, resetting it does nothing.
+func (f *TextFile) Reset() {
+	if f.standard && f.r != nil {
+		return
+	}
+
+	if f.name == "" || f.file != nil {
+		if f.file == nil {
+			panic(fmt.Errorf("reset: file is neither bound to a name nor was it written before"))
+		}
+		f.flush()
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			panic(fmt.Errorf("reset: %w", err))
+		}
+	} else {
+		var err error
+		f.file, err = os.Open(f.name)
+		if err != nil {
+			panic(fmt.Errorf("reset: %w", err))
+		}
+	}
+
+	f.w = nil
+	f.r = bufio.NewReader(f.file)
+}
+
+// Write writes the provided values to the file.
+func (f *TextFile) Write(args ...any) {
+	if f.w == nil {
+		panic(fmt.Errorf("write: file is not open for writing"))
+	}
+	write(f.w, args...)
+	if f.unbuffered {
+		f.flush()
+	}
+}
+
+// Writeln writes the provided values to the file, followed by an end of line.
+func (f *TextFile) Writeln(args ...any) {
+	f.Write(append(args, "\n")...)
+}
+
+// Page writes a form feed to the file, which starts a new page when the file is printed.
+func (f *TextFile) Page() {
+	f.Write("\f")
+}
+
+// Buffer returns a pointer to the buffer variable of the file. When the file is open
+// for reading, the buffer variable holds the next character, with line ends read as
+// blanks.
+func (f *TextFile) Buffer() *byte {
+	if f.r != nil {
+		if b, err := f.r.Peek(1); err == nil {
+			f.buffer = b[0]
+			if f.buffer == '\n' {
+				f.buffer = ' '
+			}
+		}
+	}
+	return &f.buffer
+}
+
+// Get advances the file to the next character.
+func (f *TextFile) Get() {
+	if f.r == nil {
+		panic(fmt.Errorf("get: file is not open for reading"))
+	}
+	if _, err := f.r.ReadByte(); err != nil {
+		panic(fmt.Errorf("get: %w", err))
+	}
+}
+
+// Put writes the buffer variable to the file.
+func (f *TextFile) Put() {
+	f.Write(f.buffer)
+}
+
+// Read reads values from the file into the provided variables.
+func (f *TextFile) Read(a ...any) {
+	if f.r == nil {
+		panic(fmt.Errorf("read: file is not open for reading"))
+	}
+	for _, v := range a {
+		readValue(f.r, v)
+	}
+}
+
+// Readln reads values from the file into the provided variables, and then skips
+// the remainder of the current line.
+func (f *TextFile) Readln(a ...any) {
+	f.Read(a...)
+	skipLine(f.r)
+}
+
+// Eof returns true if the end of the file has been reached. A file that is not
+// open for reading is always at its end.
+func (f *TextFile) Eof() bool {
+	if f.r == nil {
+		return true
+	}
+	_, err := f.r.Peek(1)
+	return err != nil
+}
+
+// Eoln returns true if the file is positioned at the end of a line.
+func (f *TextFile) Eoln() bool {
+	if f.r == nil {
+		return true
+	}
+	b, err := f.r.Peek(1)
+	return err != nil || b[0] == '\n'
+}
+
+func (f *TextFile) flush() {
+	if f.w != nil {
+		if err := f.w.Flush(); err != nil {
+			panic(fmt.Errorf("flush: %w", err))
+		}
+	}
+}
+
+func (f *TextFile) close() {
+	if f.file == nil {
+		return
+	}
+	f.flush()
+	f.file.Close()
+	f.file, f.r, f.w = nil, nil, nil
+}
@@ -0,0 +1,94 @@
+package system
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextFileBuffer(t *testing.T) {
+	var f TextFile
+	f.Rewrite()
+	for _, c := range []byte("ab") {
+		*f.Buffer() = c
+		f.Put()
+	}
+	f.Writeln()
+
+	f.Reset()
+	require.Equal(t, byte('a'), *f.Buffer())
+	f.Get()
+	require.Equal(t, byte('b'), *f.Buffer())
+	f.Get()
+	require.True(t, f.Eoln())
+	require.Equal(t, byte(' '), *f.Buffer())
+	f.Get()
+	require.True(t, f.Eof())
+	f.Close()
+}
+
+func TestTextFileResetStandardInput(t *testing.T) {
+	f := &TextFile{r: bufio.NewReader(strings.NewReader("first\nsecond\n")), standard: true}
+
+	var line string
+	f.Readln(&line)
+	f.Reset()
+	f.Readln(&line)
+	require.Equal(t, "second", line)
+}
+
+func TestTextFileLineTerminators(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "lines.txt")
+
+	var f TextFile
+	f.Assign(fileName)
+
+	f.Rewrite()
+	f.Writeln("first")
+	f.Close()
+
+	content, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	require.Equal(t, "first\n", string(content))
+
+	// programs that are transpiled with a different line terminator write it explicitly.
+	f.Rewrite()
+	f.Write("first", "\r\n")
+	f.Write("second", 2, "\r\n")
+	f.Close()
+
+	content, err = os.ReadFile(fileName)
+	require.NoError(t, err)
+	require.Equal(t, "first\r\nsecond2\r\n", string(content))
+
+	var line string
+	f.Reset()
+	f.Readln(&line)
+	require.Equal(t, "first", line)
+	f.Close()
+}
+
+func TestTextFileRewriteStandardOutput(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "stdout.txt")
+	file, err := os.Create(fileName)
+	require.NoError(t, err)
+
+	f := &TextFile{file: file, w: bufio.NewWriter(file), standard: true}
+	f.Write("first")
+	f.Rewrite()
+
+	content, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	require.Equal(t, "first", string(content))
+
+	f.Writeln(" line")
+	f.Close()
+
+	content, err = os.ReadFile(fileName)
+	require.NoError(t, err)
+	require.Equal(t, "first line\n", string(content))
+}
@@ -1,18 +1,94 @@
 package system
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
 
 func Write(args ...any) {
-	for _, arg := range args {
-		if b, isByte := arg.(byte); isByte {
-			fmt.Printf("%c", b)
-		} else {
-			fmt.Print(arg)
-		}
-	}
+	write(os.Stdout, args...)
 }
 
 func Writeln(args ...any) {
 	Write(args...)
 	fmt.Println("")
 }
+
+// Page writes a form feed to the standard output, which starts a new page when the
+// output is printed.
+func Page() {
+	Write("\f")
+}
+
+// write writes the provided values. Values are distinguished by their kind rather than their
+// type, as subranges and enumerated types are transpiled to named types.
+func write(w io.Writer, args ...any) {
+	for _, arg := range args {
+		switch v := reflect.ValueOf(arg); v.Kind() {
+		case reflect.Uint8:
+			fmt.Fprintf(w, "%c", v.Uint())
+		default:
+			fmt.Fprint(w, arg)
+		}
+	}
+}
+
+// formatReal formats a real in the floating-point representation of ISO Pascal, i.e. a blank
+// or minus sign followed by the real in scientific notation, with as many decimal places
+// as fit into the field width.
+func formatReal(v float64, width int) string {
+	const expDigits = 2
+
+	decimalPlaces := width - expDigits - 5 // sign, first digit, decimal point, "e" and exponent sign.
+	if decimalPlaces < 1 {
+		decimalPlaces = 1
+	}
+
+	return fmt.Sprintf("%*s", width, fmt.Sprintf("% .*e", decimalPlaces, v))
+}
+
+// Format formats a value that is written with a field width, as in write(x:width). Numbers,
+// chars and booleans are right-aligned within the field width, and reals are written in
+// floating-point representation. Strings and booleans that are longer than the field width
+// are truncated.
+func Format(v any, width int) string {
+	if width < 1 {
+		panic(fmt.Errorf("write: field width has to be positive, got %d", width))
+	}
+
+	// values are distinguished by their kind, as subranges are transpiled to named types.
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Int:
+		return fmt.Sprintf("%*d", width, rv.Int())
+	case reflect.Float64:
+		return formatReal(rv.Float(), width)
+	case reflect.Uint8:
+		return fmt.Sprintf("%*c", width, rv.Uint())
+	case reflect.Int32:
+		return fmt.Sprintf("%*c", width, rv.Int())
+	case reflect.Bool:
+		return padString(fmt.Sprint(rv.Bool()), width)
+	case reflect.String:
+		return padString(rv.String(), width)
+	}
+	return fmt.Sprint(v)
+}
+
+// FormatFixed formats a real in fixed-point representation with the provided number of
+// decimal places, right-aligned within the field width, as in write(x:width:decimalPlaces).
+func FormatFixed(v float64, width int, decimalPlaces int) string {
+	if width < 1 || decimalPlaces < 1 {
+		panic(fmt.Errorf("write: field width and decimal places have to be positive, got %d and %d", width, decimalPlaces))
+	}
+	return fmt.Sprintf("%*.*f", width, decimalPlaces, v)
+}
+
+func padString(s string, width int) string {
+	if len(s) >= width {
+		return s[:width]
+	}
+	return strings.Repeat(" ", width-len(s)) + s
+}
@@ -0,0 +1,68 @@
+package system
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// offset and letter are named types like the ones that subranges are transpiled to.
+type (
+	offset int
+	letter byte
+)
+
+func TestWrite(t *testing.T) {
+	testData := []struct {
+		args     []any
+		expected string
+	}{
+		{[]any{42, -7, 1.5}, "42-71.5"},
+		{[]any{"abc", byte('x'), true}, "abcxtrue"},
+		{[]any{offset(3), letter('x')}, "3x"},
+	}
+
+	for _, tt := range testData {
+		var buf strings.Builder
+		write(&buf, tt.args...)
+		require.Equal(t, tt.expected, buf.String(), "args %v", tt.args)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	testData := []struct {
+		value    any
+		width    int
+		expected string
+	}{
+		{42, 5, "   42"},
+		{-42, 1, "-42"},
+		{byte('x'), 3, "  x"},
+		{'y', 2, " y"},
+		{true, 6, "  true"},
+		{false, 3, "fal"},
+		{"abc", 5, "  abc"},
+		{"abcdef", 4, "abcd"},
+		{1.5, 10, " 1.500e+00"},
+		{42, 11, "         42"},
+		{1.5, 22, " 1.500000000000000e+00"},
+		{-0.03125, 22, "-3.125000000000000e-02"},
+		{2.0, 4, " 2.0e+00"},
+		{offset(5), 4, "   5"},
+		{letter('z'), 2, " z"},
+	}
+
+	for _, tt := range testData {
+		require.Equal(t, tt.expected, Format(tt.value, tt.width), "%v with width %d", tt.value, tt.width)
+	}
+
+	require.Panics(t, func() { Format(1, 0) })
+}
+
+func TestFormatFixed(t *testing.T) {
+	require.Equal(t, " 3.14", FormatFixed(3.14159, 5, 2))
+	require.Equal(t, "-3.1", FormatFixed(-3.14159, 1, 1))
+	require.Equal(t, "    37.78", FormatFixed(37.7777, 9, 2))
+	require.Panics(t, func() { FormatFixed(1.0, 5, 0) })
+}
@@ -4,45 +4,88 @@ import "text/template"
 
 var (
 	tmplFuncs = template.FuncMap{
-		"toGoTypeDef":              toGoTypeDef,
-		"toGoType":                 toGoType,
-		"sortTypeDefs":             sortTypeDefs,
-		"constantLiteral":          constantLiteral,
-		"constantLiteralList":      constantLiteralList,
-		"formalParams":             formalParams,
-		"actualParams":             actualParams,
-		"toExpr":                   toExpr,
-		"generateEnumValue":        generateEnumValue,
-		"isBuiltinProcedure":       isBuiltinProcedure,
-		"generateBuiltinProcedure": generateBuiltinProcedure,
-		"assignment":               assignment,
-		"isBooleanType":            isBooleanType,
-		"booleanForLoop":           booleanForLoop,
+		"sortTypeDefs":          sortTypeDefs,
+		"generateEnumValue":     generateEnumValue,
+		"isBuiltinProcedure":    isBuiltinProcedure,
+		"isBooleanType":         isBooleanType,
+		"exportedName":          exportedName,
+		"exportedRoutineName":   exportedRoutineName,
+		"paramNames":            paramNames,
+		"isElseIf":              isElseIf,
+		"hasWithAliases":        hasWithAliases,
+		"hasCyclicTypes":        hasCyclicTypes,
+		"localTypes":            localTypes,
+		"hasTypedConstants":     hasTypedConstants,
+		"typedConstants":        typedConstants,
+		"withoutTypedConstants": withoutTypedConstants,
 	}
-	transpilerTemplate = template.Must(template.New("").Funcs(tmplFuncs).Parse(sourceTemplate))
+
+	// the generator's functions are only declared here, they are bound to the generator of each transpilation.
+	transpilerTemplate = template.Must(template.New("").Funcs(tmplFuncs).Funcs((*generator)(nil).funcs()).Parse(sourceTemplate))
 )
 
 const sourceTemplate = `
 {{- define "main" -}}
-package main
-
+package {{ .PackageName }}
+{{ if or .UsesSystem .Imports }}
 import (
+	{{- if .UsesSystem }}
 	"github.com/akrennmair/pascal/pas2go/system"
+	{{- end }}
+	{{- range .Imports }}
+	{{ printf "%q" . }}
+	{{- end }}
 )
-
+{{ end }}
+{{- if .UsesSystem }}
 var _ = system.Write
+{{ end }}
+{{- .Body }}
+{{- end }}
 
+{{- define "body" }}
+{{- if .HasTopLevelRoutines }}
+{{- template "topLevelBlock" .Block }}
+{{- end }}
+{{- if .IsLibrary }}
+{{- template "exportedFunctions" .Block.Procedures }}
+{{- template "exportedFunctions" .Block.Functions }}
+// program {{ .Name }}
+func Main() {
+	{{- template "statements" .Block.Statements }}
+}
+{{- else if .HasTopLevelRoutines }}
+// program {{ .Name }}
+func main() {
+	{{- template "statements" .Block.Statements }}
+}
+{{- else }}
+{{- if hasCyclicTypes .Block.Types }}
+{{- template "types" .Block.Types }}
+{{- end }}
 // program {{ .Name }}
 func main() {
 	{{- template "block" .Block }}
 }
+{{- end }}
 {{ end }}
 
+{{- define "topLevelBlock" }}
+{{- template "constants" .Constants }}
+{{- template "types" .Types }}
+{{- template "enumValues" .EnumValues }}
+{{- template "globalVariables" .Variables }}
+{{- template "topLevelFunctions" .Procedures }}
+{{- template "topLevelFunctions" .Functions }}
+{{- end }}
+
 {{- define "block" }}
 	{{- template "constants" .Constants }}
-	{{- template "types" .Types }}
+	{{- template "types" (localTypes .) }}
 	{{- template "enumValues" .EnumValues }}
 	{{- template "variables" .Variables }}
+	{{- template "functionDecls" .Procedures }}
+	{{- template "functionDecls" .Functions }}
 	{{- template "functions" .Procedures }}
 	{{- template "functions" .Functions }}
 	{{- template "statements" .Statements }}
@@ -82,7 +125,7 @@ func main() {
 	{{- if . }}
 	var (
 	{{- range $var := . }}
-		{{ $var.Name }} {{ $var.Type | toGoType }}
+		{{ $var.Name }} {{ $var.Type | toGoType }}{{ if $var.InitialValue }} = {{ $var.InitialValue | constantLiteral }}{{ end }}
 	{{- end }}
 	)
 
@@ -92,13 +135,56 @@ func main() {
 	{{ end -}}
 {{ end }}
 
-{{- define "functions" }}
+{{- define "globalVariables" }}
+	{{- if . }}
+var (
+	{{- range $var := . }}
+	{{ $var.Name }} {{ $var.Type | toGoType }}{{ if $var.InitialValue }} = {{ $var.InitialValue | constantLiteral }}{{ end }}
+	{{- end }}
+)
+	{{ end -}}
+{{ end }}
+
+{{- define "topLevelFunctions" }}
+	{{- range $routine := . }}
+	{{- template "globalVariables" (hoistTypedConstants $routine) }}
+func {{ $routine | routineName }}({{ $routine.FormalParameters | formalParams }}){{ if $routine.ReturnType }} ({{ $routine | routineName }}_ {{ $routine.ReturnType | toGoType }}){{ end }} {
+	{{- template "block" (withoutTypedConstants $routine.Block) }}
+	return
+}
+	{{ end -}}
+{{ end }}
+
+{{- define "exportedFunctions" }}
+	{{- range $routine := . }}
+func {{ $routine.Name | exportedRoutineName }}({{ $routine.FormalParameters | formalParams }}){{ if $routine.ReturnType }} {{ $routine.ReturnType | toGoType }}{{ end }} {
+	{{ if $routine.ReturnType }}return {{ end }}{{ $routine | routineName }}({{ $routine.FormalParameters | paramNames }})
+}
+	{{ end -}}
+{{ end }}
+
+{{- define "functionDecls" }}
 	{{- range $routine := . }}
 		var {{ $routine.Name }} func({{ $routine.FormalParameters | formalParams }}){{ if $routine.ReturnType }} {{ $routine.ReturnType | toGoType }}{{ end }}
+	{{- end }}
+{{- end }}
+
+{{- define "functions" }}
+	{{- range $routine := . }}
+		{{- if hasTypedConstants $routine.Block }}
+		{{ $routine.Name }} = func() func({{ $routine.FormalParameters | formalParams }}){{ if $routine.ReturnType }} {{ $routine.ReturnType | toGoType }}{{ end }} {
+			{{- template "variables" (typedConstants $routine.Block) }}
+			return func({{ $routine.FormalParameters | formalParams }}){{ if $routine.ReturnType }} ({{ $routine.Name }}_ {{ $routine.ReturnType | toGoType }}){{ end }} {
+				{{- template "block" (withoutTypedConstants $routine.Block) }}
+				return
+			}
+		}()
+		{{- else }}
 		{{ $routine.Name }} = func({{ $routine.FormalParameters | formalParams }}){{ if $routine.ReturnType }} ({{ $routine.Name }}_ {{ $routine.ReturnType | toGoType }}){{ end }} {
 			{{- template "block" $routine.Block }}
 			return
 		}
+		{{- end }}
 	{{ end -}}
 {{ end }}
 
@@ -118,13 +204,19 @@ func main() {
 	{{- else if eq .Type 1 }}{{/* assignment */}}
 		{{ . | assignment }}
 	{{- else if eq .Type 2 }}{{/* procedure call */}}
-		{{ if isBuiltinProcedure .Name -}}
+		{{ if isBuiltinProcedure . -}}
 			{{ generateBuiltinProcedure . }}
 		{{- else -}}
-			{{ .Name }}{{  actualParams .ActualParams .FormalParams }}
+			{{ routineName .Routine }}{{  actualParams .ActualParams .FormalParams }}
 		{{- end }}
 	{{- else if eq .Type 3 }}{{/* compound statement */}}
-		{{- template "statements" .Statements }}
+		{{- if .Label }}
+		{
+			{{- template "statements" .Statements }}
+		}
+		{{- else }}
+			{{- template "statements" .Statements }}
+		{{- end }}
 	{{- else if eq .Type 4 }}{{/* while statement */}}
 		for {{ template "expr" .Condition }} {
 			{{ template "statement" .Statement }}
@@ -146,13 +238,7 @@ func main() {
 			{{- template "statement" .Statement }}
 		}
 	{{- else if eq .Type 7 }}{{/* if statement */}}
-		if {{ template "expr" .Condition }} {
-			{{- template "statement" .Statement }}
-		}
-		{{- if .ElseStatement }} else {
-			{{- template "statement" .ElseStatement }}
-		}
-		{{- end }}
+		{{ template "ifStatement" . }}
 	{{- else if eq .Type 8 }}{{/* case statement */}}
 		switch {{ template "expr" .Expr }} {
 		{{- range $caseLimb := .CaseLimbs }}
@@ -161,14 +247,31 @@ func main() {
 		{{- end }}
 		}
 	{{- else if eq .Type 9 }}{{/* with statement */}}
+		{{- if hasWithAliases . }}
+		{
+			{{ declareWithAliases . }}
+			{{- template "statements" .Block.Statements }}
+		}
+		{{- else }}
 		{{ template "statements" .Block.Statements }}
+		{{- end }}
 	{{- else if eq .Type 10 }}{{/* write statement */}}
-		system.Write{{ if .AppendNewLine }}ln{{ end }}{{ actualParams .ActualParams nil }}
+		{{ with overriddenWrite . }}{{ . }}{{ else }}{{ if .FileVar }}{{ template "expr" .FileVar }}.{{ writeFunc . }}{{ else }}{{ system (writeFunc .) }}{{ end }}{{ writeParams . }}{{ end }}
 	{{- else }}
 	// bug: invalid statement type {{ .Type }}
 	{{- end }}
 {{- end }}
 
+{{- define "ifStatement" }}if {{ template "expr" .Condition }} {
+			{{- template "statement" .Statement }}
+		}
+		{{- if .ElseStatement }} else {{ if isElseIf .ElseStatement }}{{ template "ifStatement" .ElseStatement }}{{ else }}{
+			{{- template "statement" .ElseStatement }}
+		}
+		{{- end }}
+		{{- end }}
+{{- end }}
+
 {{- define "expr" }}
 {{- . | toExpr }}
 {{- end }}
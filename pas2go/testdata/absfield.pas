@@ -0,0 +1,26 @@
+program absfield;
+
+type point = record
+		x : integer;
+		y : real
+	end;
+
+var a : array[1..3] of integer;
+	b : array[1..2] of real;
+	r : point;
+	i : integer;
+
+begin
+	a[1] := -3;
+	a[2] := 4;
+	a[3] := -5;
+	b[1] := -1.5;
+	b[2] := 2.5;
+	r.x := -6;
+	r.y := -0.5;
+	for i := 1 to 3 do
+		writeln(abs(a[i]), ', ', sqr(a[i]));
+	writeln(abs(b[1]):4:1, ', ', sqr(b[2]):5:2);
+	writeln(abs(r.x), ', ', sqr(r.x));
+	writeln(abs(r.y):4:1, ', ', sqr(r.y):5:2)
+end.
@@ -0,0 +1,14 @@
+program absformat;
+
+var x : real;
+	i : integer;
+
+begin
+	x := -3.14159;
+	i := -42;
+	writeln('<<', abs(x):5:2, '>>');
+	writeln('<<', abs(x):10:3, '>>');
+	writeln('<<', abs(x - 10.0):8:1, '>>');
+	writeln('<<', abs(i):5, '>>');
+	writeln('<<', abs(i) * 2:1, '>>')
+end.
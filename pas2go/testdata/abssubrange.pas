@@ -0,0 +1,14 @@
+program test;
+
+type offset = -10..10;
+
+var x : -10..10;
+	o : offset;
+	i : integer;
+
+begin
+	x := -7;
+	o := -3;
+	i := abs(x) + abs(o);
+	writeln('abs(x) = ', abs(x), ', abs(o) = ', abs(o), ', i = ', i)
+end.
@@ -0,0 +1,29 @@
+program arraycopy;
+
+type colour = (red, green, blue);
+
+var a, b : array[1..3] of integer;
+	c, d : array[colour] of integer;
+	e, f : array[char] of integer;
+	g, h : array[boolean] of integer;
+	i : integer;
+
+begin
+	for i := 1 to 3 do
+		b[i] := i;
+	a := b;
+	b[2] := 20;
+	writeln(a[1], ', ', a[2], ', ', a[3], ', ', b[2]);
+	d[green] := 5;
+	c := d;
+	d[green] := 50;
+	writeln(c[green], ', ', d[green]);
+	f['x'] := 7;
+	e := f;
+	f['x'] := 70;
+	writeln(e['x'], ', ', f['x']);
+	h[true] := 9;
+	g := h;
+	h[true] := 90;
+	writeln(g[true], ', ', h[true])
+end.
@@ -0,0 +1,48 @@
+program arrval;
+
+type
+	colour = (red, green, blue);
+	vector = array[1..3] of integer;
+	palette = array[colour] of integer;
+
+var
+	v : vector;
+	p : palette;
+	c : colour;
+
+procedure modify(a : vector);
+begin
+	a[1] := 100;
+	writeln('inside value: ', a[1])
+end;
+
+procedure modifyvar(var a : vector);
+begin
+	a[2] := 200
+end;
+
+procedure modifypalette(a : palette);
+begin
+	a[green] := 0
+end;
+
+procedure modifypalettevar(var a : palette);
+begin
+	a[blue] := 0
+end;
+
+begin
+	v[1] := 1;
+	v[2] := 2;
+	v[3] := 3;
+	modify(v);
+	writeln('after value: ', v[1]);
+	modifyvar(v);
+	writeln('after var: ', v[2]);
+	for c := red to blue do
+		p[c] := 10;
+	modifypalette(p);
+	writeln('after palette value: ', p[green]);
+	modifypalettevar(p);
+	writeln('after palette var: ', p[blue])
+end.
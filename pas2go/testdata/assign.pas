@@ -0,0 +1,22 @@
+program assigntest;
+
+var
+	f : text;
+	line : string;
+
+begin
+	assign(f, 'assign.txt');
+	rewrite(f);
+	writeln(f, 'first line');
+	writeln(f, 'second line');
+	close(f);
+
+	assign(f, 'assign.txt');
+	reset(f);
+	while not eof(f) do
+	begin
+		readln(f, line);
+		writeln('read: ', line)
+	end;
+	close(f)
+end.
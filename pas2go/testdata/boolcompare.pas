@@ -0,0 +1,17 @@
+program boolcompare;
+
+var a, b : integer;
+	c, flag : boolean;
+
+begin
+	a := 1;
+	b := 2;
+	c := true;
+	flag := (a < b) = c;
+	writeln(flag);
+	flag := (a > b) <> c;
+	writeln(flag);
+	c := false;
+	flag := (a < b) = c;
+	writeln(flag)
+end.
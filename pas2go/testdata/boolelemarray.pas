@@ -0,0 +1,14 @@
+program test;
+
+var flags : array[1..8] of boolean;
+	i, count : integer;
+
+begin
+	for i := 1 to 8 do
+		flags[i] := odd(i);
+	count := 0;
+	for i := 1 to 8 do
+		if flags[i] then
+			count := count + 1;
+	writeln('count = ', count)
+end.
@@ -0,0 +1,14 @@
+program test;
+
+var counts : array[boolean] of integer;
+	i : integer;
+	b : boolean;
+
+begin
+	counts[false] := 0;
+	counts[true] := 0;
+	for i := 1 to 5 do
+		counts[odd(i)] := counts[odd(i)] + 1;
+	for b := false to true do
+		writeln(b, ': ', counts[b])
+end.
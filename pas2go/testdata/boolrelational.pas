@@ -0,0 +1,19 @@
+program test;
+
+var p, q, r : boolean;
+	a, b : integer;
+
+begin
+	p := true;
+	q := false;
+	r := false;
+	a := 1;
+	b := 2;
+	writeln(p or q = r);
+	writeln(p and q = r);
+	writeln(r = p or q);
+	writeln(not p = q);
+	writeln((a < b) = p);
+	writeln(p <> (q or r));
+	writeln(a + b * 2 = 5 * a)
+end.
@@ -0,0 +1,22 @@
+program boolset;
+
+var flags, other : set of boolean;
+	b : boolean;
+
+begin
+	flags := [true];
+	other := flags + [false];
+	b := false;
+	if true in flags then
+		writeln('true in flags');
+	if not (b in flags) then
+		writeln('false not in flags');
+	if b in other then
+		writeln('false in other');
+	flags := [];
+	if not (true in flags) then
+		writeln('flags is empty');
+	flags := [false..true];
+	if (true in flags) and (false in flags) then
+		writeln('flags is full')
+end.
@@ -0,0 +1,20 @@
+program test;
+
+var x, a, b, c : integer;
+
+begin
+	a := 0;
+	b := 0;
+	c := 0;
+	for x := 1 to 3 do
+		case x of
+			1: begin
+				a := 1;
+				b := 2
+			end;
+			2: c := 3;
+			3: begin
+			end
+		end;
+	writeln('a = ', a, ', b = ', b, ', c = ', c)
+end.
@@ -0,0 +1,21 @@
+program charcompare;
+
+var c1, c2 : char;
+	lower : 'a'..'z';
+
+begin
+	c1 := 'a';
+	c2 := 'b';
+	if c1 <= c2 then
+		writeln('a <= b');
+	if c2 <= c1 then
+		writeln('b <= a');
+	c2 := 'a';
+	if c1 <= c2 then
+		writeln('a <= a');
+	lower := 'm';
+	if c1 < lower then
+		writeln('a < m');
+	if lower <= c1 then
+		writeln('m <= a')
+end.
@@ -0,0 +1,16 @@
+program charsubrangeset;
+
+type lower = 'a'..'z';
+
+var s : set of lower;
+	c : lower;
+
+begin
+	s := ['a'..'e', 'x'];
+	c := 'c';
+	if c in s then
+		writeln('c is in s');
+	c := 'q';
+	if not (c in s) then
+		writeln('q is not in s')
+end.
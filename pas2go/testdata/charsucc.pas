@@ -0,0 +1,16 @@
+program charsucc;
+
+const last = 'z';
+
+var c, d : char;
+
+begin
+	c := 'a';
+	c := succ(c);
+	d := pred(c);
+	writeln(c, d);
+	c := succ('y');
+	d := pred(succ(succ(d)));
+	writeln(c, d);
+	writeln(succ('a'), pred(last))
+end.
@@ -0,0 +1,13 @@
+program test;
+
+const c = 'z';
+
+var ch : char;
+	i : integer;
+
+begin
+	ch := chr(ord('a'));
+	writeln(ch, chr(ord(c)));
+	i := 66;
+	writeln(chr(ord(ch)), chr(i))
+end.
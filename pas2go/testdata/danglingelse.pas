@@ -0,0 +1,21 @@
+program danglingelse;
+
+var a, b : boolean;
+	x : integer;
+
+procedure test;
+begin
+	x := 0;
+	if a then if b then x := 1 else x := 2;
+	writeln(x)
+end;
+
+begin
+	a := true;
+	b := true;
+	test;
+	b := false;
+	test;
+	a := false;
+	test
+end.
@@ -0,0 +1,14 @@
+program test;
+
+var a, b, c : integer;
+
+begin
+	a := -17;
+	b := 5;
+	c := 2;
+	writeln('a mod b div c = ', a mod b div c);
+	writeln('a div b mod c = ', a div b mod c);
+	writeln('a * b div c mod 7 = ', a * b div c mod 7);
+	writeln('a div c * b = ', a div c * b);
+	writeln('(a + b) div (c - 4) = ', (a + b) div (c - 4))
+end.
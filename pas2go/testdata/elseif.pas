@@ -0,0 +1,13 @@
+program elseif;
+
+var i : integer;
+
+begin
+	for i := 1 to 3 do
+		if i = 1 then
+			writeln('one')
+		else if i = 2 then
+			writeln('two')
+		else
+			writeln('many')
+end.
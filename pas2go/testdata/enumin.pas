@@ -0,0 +1,19 @@
+program enumin;
+
+type colour = (red, green, blue, yellow);
+
+var c : colour;
+	warm : set of colour;
+
+begin
+	warm := [red, yellow];
+	for c := red to yellow do
+	begin
+		if c in [red, green] then
+			writeln(c, ' is red or green');
+		if c in warm then
+			writeln(c, ' is warm');
+		if not (c in [blue]) then
+			writeln(c, ' is not blue')
+	end
+end.
@@ -0,0 +1,18 @@
+program test;
+
+type colour = (red, green, blue);
+
+var a : array[colour] of integer;
+	b : array[green..blue] of integer;
+	c : colour;
+
+begin
+	a[red] := 1;
+	a[succ(red)] := 2;
+	a[blue] := 3;
+	b[green] := 4;
+	b[succ(green)] := 5;
+	for c := red to blue do
+		writeln('a[', ord(c), '] = ', a[c]);
+	writeln('b[green] = ', b[green], ', b[blue] = ', b[blue])
+end.
@@ -0,0 +1,11 @@
+program ecase;
+type colour = (red, green, blue, yellow);
+	primary = red..blue;
+var p : primary;
+begin
+	p := green;
+	case p of
+		red: writeln('red');
+		green, blue: writeln('green or blue')
+	end
+end.
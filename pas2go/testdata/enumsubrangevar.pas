@@ -0,0 +1,16 @@
+program test;
+
+type primary = (red, green, blue);
+	warm = red..green;
+
+var c : warm;
+	d : green..blue;
+	p : primary;
+
+begin
+	c := red;
+	d := blue;
+	p := c;
+	c := green;
+	writeln(ord(c), ', ', ord(d), ', ', ord(p))
+end.
@@ -0,0 +1,40 @@
+program exittest;
+
+var m : array[1..3, 1..3] of integer;
+	i, j : integer;
+
+function findfirst(target : integer) : integer;
+var i, j : integer;
+begin
+	findfirst := -1;
+	for i := 1 to 3 do
+		for j := 1 to 3 do
+			if m[i, j] = target then
+			begin
+				findfirst := i * 10 + j;
+				exit
+			end
+end;
+
+procedure countdown(n : integer);
+begin
+	while true do
+	begin
+		if n = 0 then
+			exit;
+		writeln(n);
+		n := n - 1
+	end
+end;
+
+begin
+	for i := 1 to 3 do
+		for j := 1 to 3 do
+			m[i, j] := i * j;
+	writeln(findfirst(6));
+	writeln(findfirst(7));
+	countdown(2);
+	if findfirst(9) > 0 then
+		exit;
+	writeln('9 not found')
+end.
@@ -0,0 +1,20 @@
+program test;
+
+var f : file of integer;
+	sum : integer;
+
+begin
+	rewrite(f);
+	f^ := 5;
+	put(f);
+	f^ := 7;
+	put(f);
+	reset(f);
+	sum := 0;
+	while not eof(f) do
+	begin
+		sum := sum + f^;
+		get(f)
+	end;
+	writeln('sum = ', sum)
+end.
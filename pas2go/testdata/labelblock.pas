@@ -0,0 +1,12 @@
+program lbl;
+label 10;
+var i : integer;
+begin
+	i := 0;
+10: begin
+		i := i + 1;
+		writeln('i = ', i)
+	end;
+	if i < 3 then goto 10;
+	writeln('done')
+end.
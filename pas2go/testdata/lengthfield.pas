@@ -0,0 +1,24 @@
+program lengthfield;
+
+type list = record
+		items : array[1..3] of string;
+		count : integer
+	end;
+
+var r : list;
+	k, n : integer;
+	s : string;
+
+begin
+	r.items[1] := 'one';
+	r.items[2] := 'three';
+	r.items[3] := '';
+	r.count := 3;
+	for k := 1 to r.count do
+	begin
+		n := length(r.items[k]);
+		writeln(k, ': ', n)
+	end;
+	s := 'hello';
+	writeln(length(s) + length(r.items[2]))
+end.
@@ -0,0 +1,27 @@
+program linkedlist;
+
+type pnode = ^node;
+	node = record
+		value : integer;
+		next : pnode
+	end;
+
+var head, p : pnode;
+	i : integer;
+
+begin
+	head := nil;
+	for i := 1 to 3 do
+	begin
+		new(p);
+		p^.value := i;
+		p^.next := head;
+		head := p
+	end;
+	p := head;
+	while p <> nil do
+	begin
+		writeln(p^.value);
+		p := p^.next
+	end
+end.
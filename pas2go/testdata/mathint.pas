@@ -0,0 +1,13 @@
+program test;
+
+var x, n : integer;
+
+begin
+	x := 0;
+	n := 16;
+	writeln('sin(x) = ', sin(x));
+	writeln('cos(x) = ', cos(x));
+	writeln('sqrt(n) = ', sqrt(n));
+	writeln('sqrt(n div 4) = ', sqrt(n div 4));
+	writeln('arctan(x) = ', arctan(x))
+end.
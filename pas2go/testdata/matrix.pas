@@ -0,0 +1,24 @@
+program matrix;
+
+type
+	rows = 1..3;
+	cols = 5..8;
+	grid = array[rows, cols] of real;
+
+var g : grid;
+	r : rows;
+	c : cols;
+	i, j : integer;
+	sum : real;
+
+begin
+	for i := 1 to 3 do
+		for j := 5 to 8 do
+			g[i, j] := 0.5 * j + i;
+	sum := 0.0;
+	for r := 1 to 3 do
+		for c := 5 to 8 do
+			sum := sum + g[r, c];
+	writeln(sum);
+	writeln(g[3, 8])
+end.
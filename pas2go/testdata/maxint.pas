@@ -0,0 +1,12 @@
+program test;
+
+var x, y : integer;
+
+begin
+	x := maxint div 2;
+	if x < maxint then
+		writeln('x is less than maxint');
+	y := -maxint;
+	if (y < 0) and (maxint - x > x) then
+		writeln('y = ', y)
+end.
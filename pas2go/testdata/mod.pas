@@ -0,0 +1,13 @@
+program test;
+
+const r = -7 mod 3;
+	s = 2 * 3 + 10 div 4;
+
+var i : integer;
+
+begin
+	i := -7;
+	writeln('r = ', r, ', s = ', s);
+	writeln('i mod 3 = ', i mod 3);
+	writeln('i * 2 mod 5 = ', i * 2 mod 5)
+end.
@@ -0,0 +1,25 @@
+program mutualptr;
+
+type pa = ^a;
+	pb = ^b;
+	a = record
+		n : integer;
+		next : pb
+	end;
+	b = record
+		n : integer;
+		next : pa
+	end;
+
+var x : pa;
+	y : pb;
+
+begin
+	new(x);
+	new(y);
+	x^.n := 1;
+	x^.next := y;
+	y^.n := 2;
+	y^.next := x;
+	writeln('sum = ', x^.n + x^.next^.n + x^.next^.next^.n)
+end.
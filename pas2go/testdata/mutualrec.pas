@@ -0,0 +1,31 @@
+program test;
+
+var n : integer;
+
+function isodd(i : integer) : boolean; forward;
+
+function iseven(i : integer) : boolean;
+begin
+	if i = 0 then
+		iseven := true
+	else
+		iseven := isodd(i - 1)
+end;
+
+function isodd(i : integer) : boolean;
+begin
+	if i = 0 then
+		isodd := false
+	else
+		isodd := iseven(i - 1)
+end;
+
+procedure show(i : integer);
+begin
+	writeln(i, ' is even: ', iseven(i))
+end;
+
+begin
+	for n := 0 to 5 do
+		show(n)
+end.
@@ -0,0 +1,21 @@
+program namedrange;
+
+type
+	idx = 1..10;
+	vec = array[idx] of real;
+
+var
+	v : vec;
+	i : integer;
+	j : idx;
+	sum : real;
+
+begin
+	for i := 1 to 10 do
+		v[i] := i * 0.5;
+	sum := 0.0;
+	for j := 1 to 10 do
+		sum := sum + v[j];
+	writeln(sum);
+	writeln(v[10])
+end.
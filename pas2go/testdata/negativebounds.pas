@@ -0,0 +1,16 @@
+program negativebounds;
+
+const size = 10;
+
+var a : array[-size..size] of integer;
+	i : integer;
+
+begin
+	for i := -size to size do
+		a[i] := i * i;
+	a[0] := 100;
+	a[-10] := -1;
+	i := -3;
+	writeln('a[-10] = ', a[-10], ', a[0] = ', a[0], ', a[10] = ', a[10]);
+	writeln('a[i] = ', a[i], ', a[-i] = ', a[-i], ', a[i - 7] = ', a[i - 7])
+end.
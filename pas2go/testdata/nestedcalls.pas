@@ -0,0 +1,51 @@
+program nestedcalls;
+
+type point = record
+		x, y : integer
+	end;
+
+var a : array[1..3] of integer;
+	p : point;
+	q : ^point;
+	i : integer;
+
+function double(n : integer) : integer;
+begin
+	double := 2 * n
+end;
+
+procedure addto(var target : integer; amount : integer);
+begin
+	target := target + amount
+end;
+
+procedure swap(var l, r : integer);
+var t : integer;
+begin
+	t := l;
+	l := r;
+	r := t
+end;
+
+begin
+	for i := 1 to 3 do
+		a[i] := i;
+	p.x := 10;
+	p.y := 20;
+	new(q);
+	q^.x := 5;
+	q^.y := 6;
+
+	addto(a[1], double(double(a[2])));
+	addto(p.x, double(a[3]) + 1);
+	addto(q^.y, double(p.y));
+	swap(a[2], p.y);
+	swap(q^.x, a[3]);
+	i := 0;
+	addto(i, double((a[1])));
+
+	writeln(a[1], ', ', a[2], ', ', a[3]);
+	writeln(p.x, ', ', p.y);
+	writeln(q^.x, ', ', q^.y);
+	writeln(i)
+end.
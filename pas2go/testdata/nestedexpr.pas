@@ -0,0 +1,34 @@
+program nestedexpr;
+
+var a, b, c, d : integer;
+    x, y : real;
+    p, q, r : boolean;
+
+begin
+  a := 10; b := 3; c := 2; d := 7;
+  x := 1.5; y := 4.0;
+  p := true; q := false; r := true;
+
+  { parentheses that are required by Go's precedence rules are kept }
+  writeln(a - (b + c));
+  writeln(a - (b - c));
+  writeln(a * (b + c) - d);
+  writeln((a + b) * (c + d));
+  writeln(-(a + b));
+  writeln(a div (b * c));
+  writeln((a + b) mod (c + d));
+  writeln(x / (y * 2.0));
+  writeln((p or q) and r);
+  writeln(not (p and q));
+
+  { redundant parentheses are removed }
+  writeln(((a)) + ((b) * (c)));
+  writeln((a - b) - c);
+  writeln((a * b) div c);
+  writeln(a + (b * (c + d)));
+  writeln(((a + b) + c) + d);
+  writeln((x * y) + (x / y));
+  writeln((a < b) or (c < d));
+  writeln(p or (q and r));
+  writeln((a + b) = (c + d + 4))
+end.
@@ -0,0 +1,18 @@
+program nestedpacked;
+
+type row = array[1..3] of integer;
+
+var m : packed array[1..2] of row;
+	i, j : integer;
+
+begin
+	for i := 1 to 2 do
+		for j := 1 to 3 do
+			m[i, j] := i * 10 + j;
+	for i := 1 to 2 do
+	begin
+		for j := 1 to 3 do
+			write(m[i][j]:4);
+		writeln
+	end
+end.
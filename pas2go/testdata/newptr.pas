@@ -0,0 +1,38 @@
+program test;
+
+type vector = array[1..3] of integer;
+	pvector = ^vector;
+	grid = array[1..2, 1..2] of real;
+	node = record
+		value : integer;
+		next : ^node
+	end;
+	pnode = ^node;
+	count = integer;
+	digit = 0..9;
+	color = (red, green, blue);
+
+var v : pvector;
+	g : ^grid;
+	n : pnode;
+	c : ^count;
+	d : ^digit;
+	col : ^color;
+
+begin
+	new(v);
+	v^[2] := 42;
+	new(g);
+	g^[1, 2] := 1.5;
+	new(n);
+	n^.value := v^[2];
+	new(n^.next);
+	n^.next^.value := 23;
+	new(c);
+	c^ := n^.next^.value;
+	new(d);
+	d^ := 7;
+	new(col);
+	col^ := green;
+	writeln(v^[2], ', ', g^[1, 2], ', ', n^.value, ', ', n^.next^.value, ', ', c^, ', ', d^, ', ', ord(col^))
+end.
@@ -0,0 +1,36 @@
+program nilassign;
+
+type
+	node = record
+		value : integer;
+		next : ^node
+	end;
+
+var
+	p : ^node;
+	r : node;
+	a : array[1..3] of ^node;
+	i : integer;
+
+begin
+	new(p);
+	p^.value := 42;
+	r.next := p;
+	for i := 1 to 3 do
+		a[i] := p;
+
+	p := nil;
+	r.next := nil;
+	i := 2;
+	a[i] := nil;
+
+	if p = nil then
+		writeln('p is nil');
+	if r.next = nil then
+		writeln('r.next is nil');
+	for i := 1 to 3 do
+		if a[i] = nil then
+			writeln('a[', i, '] is nil')
+		else
+			writeln('a[', i, '] = ', a[i]^.value)
+end.
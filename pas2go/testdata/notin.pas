@@ -0,0 +1,16 @@
+program test;
+
+var c : char;
+	vowels : set of char;
+
+begin
+	vowels := ['a', 'e', 'i', 'o', 'u'];
+	c := 'x';
+	if not (c in vowels) then
+		writeln('x is not a vowel');
+	c := 'e';
+	if not (c in vowels) then
+		writeln('e is not a vowel')
+	else
+		writeln('e is a vowel')
+end.
@@ -0,0 +1,16 @@
+program test;
+
+type
+	r = record
+		n : integer
+	end;
+	pr = ^r;
+
+var p : pr;
+
+begin
+	new(p);
+	p^.n := 42;
+	writeln('n = ', p^.n);
+	dispose(p)
+end.
@@ -0,0 +1,12 @@
+program test;
+
+type temperature = (cold = -2, cool, mild, warm);
+
+var t : temperature;
+	counts : array[temperature] of integer;
+
+begin
+	for t := cold to warm do
+		counts[t] := ord(t) * 10;
+	writeln('counts = ', counts[cold], ', ', counts[cool], ', ', counts[mild], ', ', counts[warm])
+end.
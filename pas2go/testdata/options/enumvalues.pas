@@ -0,0 +1,22 @@
+program test;
+
+type level = (low = 1, medium, high = 5);
+	upper = medium..high;
+
+var l : level;
+	u : upper;
+	counts : array[level] of integer;
+
+begin
+	l := high;
+	u := medium;
+	counts[l] := 3;
+	counts[medium] := counts[l] + 1;
+	writeln('ord(high) = ', ord(high), ', ord(u) = ', ord(u));
+	if (low < medium) and (u < l) then
+		writeln('counts = ', counts[high], ', ', counts[medium]);
+	case l of
+		low: writeln('low');
+		medium, high: writeln('medium or high')
+	end
+end.
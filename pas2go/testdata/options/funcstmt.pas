@@ -0,0 +1,22 @@
+program test;
+
+var count : integer;
+
+function compute(i : integer) : integer;
+begin
+	count := count + 1;
+	compute := i * 2
+end;
+
+function answer : integer;
+begin
+	count := count + 1;
+	answer := 42
+end;
+
+begin
+	count := 0;
+	compute(3);
+	answer;
+	writeln('count = ', count, ', compute(4) = ', compute(4))
+end.
@@ -0,0 +1,14 @@
+program isowidths;
+
+var i : integer;
+    r : real;
+
+begin
+  i := 42;
+  r := 1.5;
+  writeln(i);
+  writeln(-i, i * 1000);
+  writeln(r);
+  writeln(-r / 16);
+  writeln('i = ', i, ', r = ', r)
+end.
@@ -0,0 +1,18 @@
+program mainlib;
+
+var runs : integer;
+
+procedure main(n : integer);
+begin
+	runs := runs + n
+end;
+
+function total : integer;
+begin
+	total := runs
+end;
+
+begin
+	runs := 0;
+	main(1)
+end.
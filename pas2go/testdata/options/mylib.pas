@@ -0,0 +1,31 @@
+program mylib;
+
+type point = record
+		x, y : integer
+	end;
+
+var calls : integer;
+
+procedure count;
+begin
+	calls := calls + 1
+end;
+
+procedure swap(var a, b : integer);
+var tmp : integer;
+begin
+	count;
+	tmp := a;
+	a := b;
+	b := tmp
+end;
+
+function distance(p : point) : integer;
+begin
+	count;
+	distance := abs(p.x) + abs(p.y)
+end;
+
+begin
+	calls := 0
+end.
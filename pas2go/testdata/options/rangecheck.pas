@@ -0,0 +1,15 @@
+program rangecheck(input, output);
+
+type digit = 0..9;
+
+var d : digit;
+	n : 1..100;
+	c : 'a'..'z';
+	i : integer;
+
+begin
+	readln(d, n);
+	readln(c);
+	read(i);
+	writeln(d, ', ', n, ', ', c, ', ', i)
+end.
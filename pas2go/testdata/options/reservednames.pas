@@ -0,0 +1,28 @@
+program test;
+
+var n : integer;
+
+procedure init;
+begin
+	n := 1
+end;
+
+function main(k : integer) : integer;
+begin
+	if k <= 1 then
+		main := 1
+	else
+		main := k * main(k - 1)
+end;
+
+procedure apply(function f(x : integer) : integer);
+begin
+	n := n + f(4)
+end;
+
+begin
+	init;
+	n := n + main(3);
+	apply(main);
+	writeln('n = ', n)
+end.
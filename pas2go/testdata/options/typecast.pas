@@ -0,0 +1,20 @@
+program typecast;
+
+type colour = (red, green, blue);
+
+var ch : char;
+	n : integer;
+	c : colour;
+	b : boolean;
+
+begin
+	ch := 'A';
+	n := integer(ch);
+	writeln('integer(ch) = ', n);
+	ch := char(n + 1);
+	writeln('char(n + 1) = ', ch);
+	c := colour(n - 64);
+	writeln('ord(c) = ', ord(c), ', integer(c) = ', integer(c));
+	b := boolean(integer(c) - 1);
+	writeln('boolean = ', b, ', integer(b) = ', integer(b))
+end.
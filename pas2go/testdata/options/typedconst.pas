@@ -0,0 +1,26 @@
+program test;
+
+const n = 2;
+	m : array[1..n, 1..n] of integer = ((1, 2), (3, -4));
+	limit : integer = 10;
+
+var i, j : integer;
+
+procedure show;
+const
+	digits : array[0..2] of char = ('x', 'y', 'z');
+var i : integer;
+begin
+	for i := 0 to 2 do
+		write(digits[i]);
+	writeln
+end;
+
+begin
+	for i := 1 to n do
+		for j := 1 to n do
+			writeln('m[', i, ', ', j, '] = ', m[i, j]);
+	limit := limit + 1;
+	writeln('limit = ', limit);
+	show
+end.
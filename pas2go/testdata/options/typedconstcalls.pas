@@ -0,0 +1,25 @@
+program test;
+
+type pair = array[1..2] of integer;
+
+procedure count;
+const calls : integer = 0;
+begin
+	calls := calls + 1;
+	writeln('count was called ', calls, ' times')
+end;
+
+function next : integer;
+const last : pair = (0, 10);
+begin
+	last[1] := last[1] + 1;
+	last[2] := last[2] + 10;
+	next := last[1] * last[2]
+end;
+
+begin
+	count;
+	count;
+	writeln('next = ', next);
+	writeln('next = ', next)
+end.
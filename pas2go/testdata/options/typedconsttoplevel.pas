@@ -0,0 +1,29 @@
+program test;
+
+procedure count;
+const calls : integer = 0;
+
+	procedure report;
+	begin
+		writeln('count was called ', calls, ' times')
+	end;
+
+begin
+	calls := calls + 1;
+	report
+end;
+
+function next : integer;
+const last : array[1..2] of integer = (0, 10);
+begin
+	last[1] := last[1] + 1;
+	last[2] := last[2] + 10;
+	next := last[1] * last[2]
+end;
+
+begin
+	count;
+	count;
+	writeln('next = ', next);
+	writeln('next = ', next)
+end.
@@ -0,0 +1,9 @@
+program test;
+
+var i : integer;
+
+begin
+	i := ord(chr(65));
+	writeln(i);
+	writeln(ord(chr(i + 1)))
+end.
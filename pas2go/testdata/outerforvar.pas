@@ -0,0 +1,23 @@
+program test;
+
+var total : integer;
+
+procedure outer;
+var i : integer;
+
+	procedure inner;
+	begin
+		for i := 1 to 4 do
+			total := total + i
+	end;
+
+begin
+	i := 0;
+	inner;
+	writeln('i = ', i, ', total = ', total)
+end;
+
+begin
+	total := 0;
+	outer
+end.
@@ -0,0 +1,13 @@
+program test(output);
+
+var f : text;
+
+begin
+	writeln('first page');
+	page(output);
+	writeln(output, 'second page');
+	page;
+	writeln('third page');
+	rewrite(f);
+	page(f)
+end.
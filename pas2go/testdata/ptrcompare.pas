@@ -0,0 +1,25 @@
+program ptrcompare;
+
+type
+	node = record
+		value : integer;
+		next : ^node
+	end;
+
+var p1, p2, p3 : ^node;
+
+begin
+	new(p1);
+	p1^.value := 1;
+	p1^.next := nil;
+	p2 := p1;
+	if p1 = p2 then
+		writeln('p1 and p2 are equal');
+	new(p3);
+	if p3 <> p1 then
+		writeln('p3 and p1 differ');
+	if p1^.next = nil then
+		writeln('p1 has no successor');
+	if p3 <> nil then
+		writeln('p3 is not nil')
+end.
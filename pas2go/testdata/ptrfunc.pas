@@ -0,0 +1,26 @@
+program test;
+
+type pnode = ^node;
+	node = record
+		value : integer;
+		next : ^node
+	end;
+
+var head : pnode;
+
+function newnode(v : integer; n : pnode) : pnode;
+begin
+	new(newnode);
+	newnode^.value := v;
+	newnode^.next := n
+end;
+
+begin
+	head := newnode(2, nil);
+	head := newnode(1, head);
+	while head <> nil do
+	begin
+		writeln(head^.value);
+		head := head^.next
+	end
+end.
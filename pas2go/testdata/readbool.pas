@@ -0,0 +1,12 @@
+program test;
+
+var b : boolean;
+
+begin
+	write('enter true or false: ');
+	readln(b);
+	if b then
+		writeln('yes')
+	else
+		writeln('no')
+end.
@@ -0,0 +1,15 @@
+program readlnskip;
+
+var i, sum : integer;
+
+begin
+	{ skip the header line }
+	readln;
+	sum := 0;
+	while not eof(input) do
+	begin
+		readln(i);
+		sum := sum + i
+	end;
+	writeln('sum = ', sum)
+end.
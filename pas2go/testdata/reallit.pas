@@ -0,0 +1,25 @@
+program test;
+
+const a = 5.;
+	b = .5;
+	c = 5e3;
+	d = -0.1;
+	e = 2.5e-3;
+
+var r : real;
+
+begin
+	r := 5.;
+	writeln('r = ', r);
+	r := .5;
+	writeln('r = ', r);
+	r := 5e3;
+	writeln('r = ', r);
+	r := 0.1;
+	writeln('r = ', r);
+	r := -.25;
+	writeln('r = ', r);
+	r := 1.5E+2;
+	writeln('r = ', r);
+	writeln(a, ', ', b, ', ', c, ', ', d, ', ', e)
+end.
@@ -0,0 +1,24 @@
+program recordcopy;
+
+type point = record
+		x, y : integer
+	end;
+
+var p1, p2 : point;
+	a1, a2 : record
+		name : char;
+		value : integer
+	end;
+
+begin
+	p2.x := 1;
+	p2.y := 2;
+	p1 := p2;
+	p2.x := 10;
+	writeln(p1.x, ', ', p1.y, ', ', p2.x);
+	a2.name := 'a';
+	a2.value := 42;
+	a1 := a2;
+	a2.value := 0;
+	writeln(a1.name, ', ', a1.value, ', ', a2.value)
+end.
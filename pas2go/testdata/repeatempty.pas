@@ -0,0 +1,9 @@
+program test;
+
+var done : boolean;
+
+begin
+	done := true;
+	repeat until done;
+	writeln('done')
+end.
@@ -0,0 +1,33 @@
+program setassign;
+
+type
+	small = 1..10;
+	colour = (red, green, blue, yellow);
+	primary = red..blue;
+
+var
+	i : set of integer;
+	s : set of small;
+	c : set of colour;
+	p : set of primary;
+	b : set of boolean;
+	n : integer;
+
+begin
+	s := [2, 4];
+	i := s;
+	i := i + [6];
+	s := i;
+	for n := 1 to 10 do
+		if n in s then
+			writeln(n);
+
+	p := [red, blue];
+	c := p;
+	if blue in c then
+		writeln('blue in c');
+
+	b := [true];
+	if true in b then
+		writeln('true in b')
+end.
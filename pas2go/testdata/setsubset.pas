@@ -0,0 +1,14 @@
+program test;
+
+var a, b : set of 1..10;
+
+begin
+	a := [1, 2];
+	b := [1, 2, 3];
+	if a <= b then
+		writeln('a is a subset of b');
+	if not (b <= a) then
+		writeln('b is not a subset of a');
+	if b >= a then
+		writeln('b is a superset of a')
+end.
@@ -0,0 +1,13 @@
+program test;
+
+var a, b, c : set of 1..10;
+	i : integer;
+
+begin
+	a := [1, 2, 3];
+	b := [2, 3, 4];
+	c := a >< b + [7];
+	for i := 1 to 10 do
+		if i in c then
+			writeln(i)
+end.
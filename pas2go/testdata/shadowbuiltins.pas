@@ -0,0 +1,37 @@
+program test;
+
+var n : integer;
+
+procedure exit;
+begin
+	n := n + 1
+end;
+
+procedure close(x : integer);
+begin
+	n := n + x
+end;
+
+procedure page(x : integer);
+begin
+	n := n * x
+end;
+
+procedure new(a, b : integer);
+begin
+	n := n + a * b
+end;
+
+function length(x : integer) : integer;
+begin
+	length := x * 2
+end;
+
+begin
+	n := 0;
+	exit;
+	close(3);
+	page(2);
+	new(1, 2);
+	writeln('n = ', n, ', length = ', length(4))
+end.
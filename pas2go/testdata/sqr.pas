@@ -0,0 +1,17 @@
+program sqrtest;
+
+var
+	i : integer;
+	r : real;
+
+begin
+	i := 3;
+	r := sqr(i) + 0.5;
+	writeln(r);
+	r := 0.5 + sqr(i);
+	writeln(r);
+	r := sqr(1.5) + 0.25;
+	writeln(r);
+	i := sqr(i) + 1;
+	writeln(i)
+end.
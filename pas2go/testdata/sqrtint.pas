@@ -0,0 +1,15 @@
+program test;
+
+const four = 4;
+
+var i : integer;
+	r : real;
+
+begin
+	i := 9;
+	r := sqrt(4);
+	writeln('sqrt(4) = ', r);
+	writeln('sqrt(i) = ', sqrt(i));
+	writeln('sqrt(four) = ', sqrt(four));
+	writeln('sqrt(i + 7) = ', sqrt(i + 7))
+end.
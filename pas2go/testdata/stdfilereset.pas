@@ -0,0 +1,16 @@
+program stdfilereset(input, output);
+
+var i : integer;
+
+procedure reopen(var f : text);
+begin
+	reset(f)
+end;
+
+begin
+	reset(input);
+	rewrite(output);
+	readln(i);
+	reopen(input);
+	writeln('i = ', i)
+end.
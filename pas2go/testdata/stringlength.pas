@@ -0,0 +1,13 @@
+program test;
+
+var s : string[10];
+	a : packed array[1..10] of char;
+
+begin
+	a := 'abcdefghij';
+	s := 'hello';
+	a := s;
+	writeln('a = ', a, '!!');
+	s := a;
+	writeln('s = ', s, '!!')
+end.
@@ -0,0 +1,20 @@
+program subexprargs;
+
+var a, b, c, r : real;
+	i, j : integer;
+
+begin
+	a := 1.5;
+	b := 2.5;
+	c := 4.0;
+	i := -3;
+	j := 2;
+	r := sqrt((a + b) * c);
+	writeln(r);
+	r := sqrt((a + b));
+	writeln(r);
+	writeln(abs((i - j)));
+	writeln(sqr((i + j) * j));
+	writeln(sqr((a - b)));
+	writeln(abs(-(a * c)))
+end.
@@ -0,0 +1,17 @@
+program setsub;
+
+type small = 1..10;
+
+var s : set of small;
+	t : set of integer;
+	i : integer;
+
+begin
+	s := [1, 3, 5];
+	t := s;
+	t := t + [7];
+	s := t;
+	for i := 1 to 10 do
+		if i in s then
+			writeln(i)
+end.
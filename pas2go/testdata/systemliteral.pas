@@ -0,0 +1,7 @@
+program test;
+
+var s : string;
+
+begin
+	s := 'filesystem.x'
+end.
@@ -0,0 +1,40 @@
+program tagfield;
+
+type shapekind = (circle, rectangle);
+	shape = record
+		name : string;
+		case kind : shapekind of
+			circle: ( radius : integer );
+			rectangle: ( width, height : integer );
+		end;
+	counted = record
+		case tag : integer of
+			1: ( i : integer );
+			2: ( x : real );
+		end;
+
+var s : shape;
+	c : counted;
+
+begin
+	s.name := 'box';
+	s.kind := rectangle;
+	s.width := 3;
+	s.height := 4;
+	if s.kind = rectangle then
+		writeln(s.name, ': area = ', s.width * s.height);
+
+	c.tag := 1;
+	c.i := 42;
+	if c.tag = 1 then
+		writeln('tag = ', c.tag, ', i = ', c.i);
+	with c do
+	begin
+		tag := tag + 1;
+		x := 2.5
+	end;
+	case c.tag of
+		1: writeln('integer ', c.i);
+		2: writeln('real ', c.x)
+	end
+end.
@@ -0,0 +1,18 @@
+program texttest;
+
+var
+	f : text;
+	line : string;
+	n : integer;
+
+begin
+	rewrite(f);
+	writeln(f, 'hello world');
+	writeln(f, 42);
+	reset(f);
+	readln(f, line);
+	readln(f, n);
+	writeln('line = ', line);
+	writeln('n = ', n);
+	writeln('eof = ', eof(f))
+end.
@@ -0,0 +1,33 @@
+program typeorder;
+
+type
+	pnode = ^node;
+	pdata = ^data;
+	node = record
+		value : integer;
+		data : pdata;
+		next : ^node
+	end;
+	data = record
+		weights : array[1..3] of real;
+		tag : char
+	end;
+	holder = record
+		first : pnode;
+		count : integer
+	end;
+
+var h : holder;
+	n : pnode;
+
+begin
+	new(n);
+	n^.value := 7;
+	new(n^.data);
+	n^.data^.tag := 'x';
+	n^.data^.weights[2] := 1.5;
+	n^.next := nil;
+	h.first := n;
+	h.count := 1;
+	writeln(h.first^.value, ', ', h.first^.data^.tag, ', ', h.count)
+end.
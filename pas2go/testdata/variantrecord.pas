@@ -5,7 +5,7 @@ type foo = record
 		d : real;
 		case bla : integer of
 			1, 2, 3: ( a : real );
-			3, 4, 5: ( b : string );
+			4, 5, 6: ( b : string );
 		end;
 
 var x : foo;
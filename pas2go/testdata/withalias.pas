@@ -0,0 +1,36 @@
+program test;
+
+type rec = record
+		a, b : integer;
+		inner : record
+			c : integer
+		end
+	end;
+
+var arr : array[1..3] of rec;
+	calls : integer;
+
+function f : integer;
+begin
+	calls := calls + 1;
+	f := calls
+end;
+
+begin
+	calls := 0;
+	with arr[f] do
+	begin
+		a := 1;
+		b := 2
+	end;
+	writeln('calls = ', calls, ', a = ', arr[1].a, ', b = ', arr[1].b);
+	with arr[f + 1] do
+		with inner do
+		begin
+			a := 3;
+			c := 4;
+			with arr[f - 2].inner do
+				c := c + a
+		end;
+	writeln('calls = ', calls, ', a = ', arr[3].a, ', c = ', arr[3].inner.c, ', ', arr[1].inner.c)
+end.
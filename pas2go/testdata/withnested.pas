@@ -0,0 +1,23 @@
+program withnested;
+
+var y : record
+		a : integer;
+		b : record
+			c : real;
+			d : string;
+		end;
+	end;
+
+begin
+	with y do
+	begin
+		a := 23; { addresses y.a }
+		b.c := 23.5; { addresses y.b.c }
+		with b do
+		begin
+			d := 'hello'; { addresses y.b.d }
+			c := c + a { addresses y.b.c and y.a }
+		end
+	end;
+	writeln('a = ', y.a, ', c = ', y.b.c, ', d = ', y.b.d)
+end.
@@ -0,0 +1,22 @@
+program writechararray;
+
+type
+	name = packed array[1..5] of char;
+	person = record
+		first : name;
+		age : integer
+	end;
+
+var
+	s : name;
+	p : person;
+
+begin
+	s := 'hello';
+	writeln(s);
+	write(s);
+	writeln(', world');
+	p.first := 'alice';
+	p.age := 42;
+	writeln(p.first, ' is ', p.age)
+end.
@@ -4,14 +4,256 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"sort"
+	"text/template"
 
 	"github.com/akrennmair/pascal/parser"
 )
 
-func Transpile(ast *parser.AST) (string, error) {
+// Option configures how a Pascal program is transpiled.
+type Option func(*program)
+
+// WithPackageName sets the name of the package of the generated Go source code.
+// If the name is anything other than "main", no main function is generated. Instead,
+// the program's global declarations are emitted at the package level, its procedures
+// and functions are exported as top-level functions, and the program's statement part
+// is emitted as the exported function Main. A routine named main is exported as Main_
+// to keep it apart from the statement part.
+func WithPackageName(name string) Option {
+	return func(p *program) {
+		p.PackageName = name
+	}
+}
+
+// WithTopLevelRoutines makes the transpiler emit the program's global declarations at the
+// package level, and its procedures and functions as top-level functions rather than as
+// closures within the main function.
+func WithTopLevelRoutines() Option {
+	return func(p *program) {
+		p.TopLevelRoutines = true
+	}
+}
+
+// WithLineTerminator sets the line terminator that the generated program writes at the
+// end of each line, e.g. "\r\n". By default, lines are terminated by "\n".
+func WithLineTerminator(lineTerminator string) Option {
+	return func(p *program) {
+		p.LineTerminator = lineTerminator
+	}
+}
+
+// WithCheckedPointers makes dispose mark the disposed pointer, and makes the generated
+// program panic when a disposed pointer is dereferenced.
+func WithCheckedPointers() Option {
+	return func(p *program) {
+		p.CheckedPointers = true
+	}
+}
+
+// WithRangeChecks makes the generated program check that values that are read into
+// variables of integer or char subrange types are within the subrange, and panic with
+// a range error otherwise.
+func WithRangeChecks() Option {
+	return func(p *program) {
+		p.RangeChecks = true
+	}
+}
+
+// WithISOFieldWidths makes the generated program write integers and reals that have no
+// explicit field width with the default field widths that are conventional for ISO Pascal
+// implementations, i.e. integers right-aligned in a field of 11 characters, and reals in
+// floating-point representation in a field of 22 characters.
+func WithISOFieldWidths() Option {
+	return func(p *program) {
+		p.IntegerFieldWidth = isoIntegerFieldWidth
+		p.RealFieldWidth = isoRealFieldWidth
+	}
+}
+
+// WithBuiltinOverride makes the transpiler emit calls of the builtin procedure name with
+// the source code that is returned by emit rather than with the default implementation,
+// e.g. to integrate with a custom runtime. write and writeln statements are handed to emit
+// as procedure calls whose actual parameters are the written expressions, preceded by the
+// file variable if there is one. emit can use expr to get the Go source code of the actual
+// parameters. imports are the import paths of the packages that the emitted source code
+// refers to, which are imported if the builtin procedure is called. Only builtin procedures
+// can be overridden, Transpile returns an error for any other name.
+func WithBuiltinOverride(name string, emit func(stmt *parser.ProcedureCallStatement, expr func(parser.Expression) string) string, imports ...string) Option {
+	return func(p *program) {
+		if p.BuiltinOverrides == nil {
+			p.BuiltinOverrides = make(map[string]builtinOverride)
+		}
+		p.BuiltinOverrides[name] = builtinOverride{emit: emit, imports: imports}
+	}
+}
+
+// builtinOverride emits calls of a builtin procedure instead of the default implementation.
+type builtinOverride struct {
+	emit    func(stmt *parser.ProcedureCallStatement, expr func(parser.Expression) string) string
+	imports []string
+}
+
+const (
+	isoIntegerFieldWidth = 11
+	isoRealFieldWidth    = 22
+)
+
+// systemPackage is the import path of the runtime package of transpiled programs.
+const systemPackage = "github.com/akrennmair/pascal/pas2go/system"
+
+// program is the data that is handed to the transpiler template.
+type program struct {
+	*parser.AST
+
+	// Name of the package of the generated Go source code.
+	PackageName string
+
+	// If true, procedures and functions are emitted as top-level functions.
+	TopLevelRoutines bool
+
+	// If not empty, the line terminator that is written by writeln.
+	LineTerminator string
+
+	// If true, dereferences of disposed pointers panic.
+	CheckedPointers bool
+
+	// If true, values that are read into subrange variables are checked to be within range.
+	RangeChecks bool
+
+	// If not zero, the default field widths that integers and reals are written with.
+	IntegerFieldWidth int
+	RealFieldWidth    int
+
+	// Overrides that emit calls of builtin procedures instead of the default implementation.
+	BuiltinOverrides map[string]builtinOverride
+
+	// Generated source code of the program's declarations and statements.
+	Body string
+
+	// If true, the generated source code refers to the system package, which is then imported.
+	UsesSystem bool
+
+	// Import paths of the other packages that the generated source code refers to.
+	Imports []string
+}
+
+// IsLibrary returns true if the program is not transpiled as a main package.
+func (p *program) IsLibrary() bool {
+	return p.PackageName != "main"
+}
+
+// HasTopLevelRoutines returns true if procedures and functions are emitted as top-level functions.
+func (p *program) HasTopLevelRoutines() bool {
+	return p.TopLevelRoutines || p.IsLibrary()
+}
+
+// generator generates the Go source code of a program. It holds the state of a single
+// transpilation, so that programs can be transpiled concurrently.
+type generator struct {
+	*program
+
+	// withAliases maps the record expressions of with statements to the local variables
+	// that point to the records.
+	withAliases map[parser.Expression]string
+
+	// usesSystem is true if the generated source code refers to the system package.
+	usesSystem bool
+
+	// imports contains the import paths of the other packages that the generated source
+	// code refers to.
+	imports map[string]bool
+
+	// hoistedConstants maps the typed constants of top-level routines to the names of the
+	// package-level variables that they are declared as.
+	hoistedConstants map[*parser.Variable]string
+}
+
+// emitOverride returns the source code of a call of an overridden builtin procedure, and
+// records the packages that it refers to.
+func (g *generator) emitOverride(override builtinOverride, stmt *parser.ProcedureCallStatement) string {
+	for _, path := range override.imports {
+		if path == systemPackage {
+			g.usesSystem = true
+		} else {
+			g.imports[path] = true
+		}
+	}
+	return override.emit(stmt, g.toExpr)
+}
+
+// system returns a reference to the identifier name of the system package, and records
+// that the system package is used.
+func (g *generator) system(name string) string {
+	g.usesSystem = true
+	return "system." + name
+}
+
+// funcs returns the template functions that depend on the state of the generator.
+func (g *generator) funcs() template.FuncMap {
+	return template.FuncMap{
+		"toGoTypeDef":              g.toGoTypeDef,
+		"toGoType":                 g.toGoType,
+		"constantLiteral":          g.constantLiteral,
+		"constantLiteralList":      g.constantLiteralList,
+		"formalParams":             g.formalParams,
+		"system":                   g.system,
+		"actualParams":             g.actualParams,
+		"writeParams":              g.writeParams,
+		"writeFunc":                g.writeFunc,
+		"toExpr":                   g.toExpr,
+		"generateBuiltinProcedure": g.generateBuiltinProcedure,
+		"overriddenWrite":          g.overriddenWrite,
+		"assignment":               g.assignment,
+		"booleanForLoop":           g.booleanForLoop,
+		"declareWithAliases":       g.declareWithAliases,
+		"routineName":              g.routineName,
+		"hoistTypedConstants":      g.hoistTypedConstants,
+	}
+}
+
+// Transpile transpiles the provided AST to Go source code.
+func Transpile(ast *parser.AST, opts ...Option) (string, error) {
 	var buf bytes.Buffer
 
-	if err := transpilerTemplate.ExecuteTemplate(&buf, "main", ast); err != nil {
+	prog := &program{
+		AST:         ast,
+		PackageName: "main",
+	}
+
+	for _, opt := range opts {
+		opt(prog)
+	}
+
+	for name := range prog.BuiltinOverrides {
+		if parser.FindBuiltinProcedure(name) == nil {
+			return "", fmt.Errorf("can't override %s, as it is not a builtin procedure", name)
+		}
+	}
+
+	g := &generator{
+		program:     prog,
+		withAliases: make(map[parser.Expression]string),
+		imports:     make(map[string]bool),
+
+		hoistedConstants: make(map[*parser.Variable]string),
+	}
+
+	tmpl := template.Must(transpilerTemplate.Clone()).Funcs(g.funcs())
+
+	var body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&body, "body", prog); err != nil {
+		return "", fmt.Errorf("failed to generated Go source code: %w", err)
+	}
+
+	// the system package is only imported if it's used, as Go doesn't allow unused imports.
+	prog.Body = body.String()
+	prog.UsesSystem = g.usesSystem
+	for path := range g.imports {
+		prog.Imports = append(prog.Imports, path)
+	}
+	sort.Strings(prog.Imports)
+
+	if err := tmpl.ExecuteTemplate(&buf, "main", prog); err != nil {
 		return "", fmt.Errorf("failed to generated Go source code: %w", err)
 	}
 
@@ -1,8 +1,13 @@
 package pas2go
 
 import (
+	goparser "go/parser"
+	"go/token"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/akrennmair/pascal/parser"
@@ -15,32 +20,306 @@ func TestTranspile(t *testing.T) {
 
 	for _, pascalFile := range pascalFiles {
 		t.Run(pascalFile, func(t *testing.T) {
-			writeMode := false
+			testGoldenFile(t, pascalFile, pascalFile+".golden", nil)
+		})
+	}
+}
 
-			fileContent, err := ioutil.ReadFile(pascalFile)
-			require.NoError(t, err)
+func TestTranspileWithOptions(t *testing.T) {
+	testData := []struct {
+		name       string
+		pascalFile string
+		goldenFile string
+		parserOpts []parser.Option
+		opts       []Option
+	}{
+		{
+			"library package",
+			"testdata/options/mylib.pas",
+			"testdata/options/mylib.pas.golden",
+			nil,
+			[]Option{WithPackageName("mylib")},
+		},
+		{
+			"library package with routine named main",
+			"testdata/options/mainlib.pas",
+			"testdata/options/mainlib.pas.golden",
+			nil,
+			[]Option{WithPackageName("mainlib")},
+		},
+		{
+			"top-level routines",
+			"testdata/mutualrec.pas",
+			"testdata/options/mutualrec-toplevel.pas.golden",
+			nil,
+			[]Option{WithTopLevelRoutines()},
+		},
+		{
+			"top-level routines named main and init",
+			"testdata/options/reservednames.pas",
+			"testdata/options/reservednames.pas.golden",
+			nil,
+			[]Option{WithTopLevelRoutines()},
+		},
+		{
+			"line terminator",
+			"testdata/func.pas",
+			"testdata/options/func-crlf.pas.golden",
+			nil,
+			[]Option{WithLineTerminator("\r\n")},
+		},
+		{
+			"checked pointers",
+			"testdata/deref.pas",
+			"testdata/options/deref-checked.pas.golden",
+			nil,
+			[]Option{WithCheckedPointers()},
+		},
+		{
+			"checked pointers with named pointer type",
+			"testdata/options/disposenamed.pas",
+			"testdata/options/disposenamed.pas.golden",
+			nil,
+			[]Option{WithCheckedPointers()},
+		},
+		{
+			"explicit enum values",
+			"testdata/options/enumvalues.pas",
+			"testdata/options/enumvalues.pas.golden",
+			[]parser.Option{parser.WithExplicitEnumValues()},
+			nil,
+		},
+		{
+			"explicit negative enum values",
+			"testdata/options/enumnegative.pas",
+			"testdata/options/enumnegative.pas.golden",
+			[]parser.Option{parser.WithExplicitEnumValues()},
+			nil,
+		},
+		{
+			"typed constants",
+			"testdata/options/typedconst.pas",
+			"testdata/options/typedconst.pas.golden",
+			[]parser.Option{parser.WithTypedConstants()},
+			nil,
+		},
+		{
+			"typed constants keep their value between calls",
+			"testdata/options/typedconstcalls.pas",
+			"testdata/options/typedconstcalls.pas.golden",
+			[]parser.Option{parser.WithTypedConstants()},
+			nil,
+		},
+		{
+			"typed constants of top-level routines",
+			"testdata/options/typedconsttoplevel.pas",
+			"testdata/options/typedconsttoplevel.pas.golden",
+			[]parser.Option{parser.WithTypedConstants()},
+			[]Option{WithTopLevelRoutines()},
+		},
+		{
+			"function call statements",
+			"testdata/options/funcstmt.pas",
+			"testdata/options/funcstmt.pas.golden",
+			[]parser.Option{parser.WithFunctionCallStatements()},
+			nil,
+		},
+		{
+			"ISO field widths",
+			"testdata/options/isowidths.pas",
+			"testdata/options/isowidths.pas.golden",
+			nil,
+			[]Option{WithISOFieldWidths()},
+		},
+		{
+			"type casts",
+			"testdata/options/typecast.pas",
+			"testdata/options/typecast.pas.golden",
+			[]parser.Option{parser.WithTypeCasts()},
+			nil,
+		},
+		{
+			"range checks",
+			"testdata/options/rangecheck.pas",
+			"testdata/options/rangecheck.pas.golden",
+			nil,
+			[]Option{WithRangeChecks()},
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			testGoldenFile(t, tt.pascalFile, tt.goldenFile, tt.parserOpts, tt.opts...)
+		})
+	}
+}
 
-			goldenFile := pascalFile + ".golden"
+// testGoldenFile transpiles the Pascal file and compares the result with the golden file.
+// If the golden file doesn't exist, it is written instead. If the environment variable
+// UPDATE_GOLDEN is set to 1, all golden files are overwritten with the transpiler output,
+// e.g. after an intended change of the generated code: UPDATE_GOLDEN=1 go test ./pas2go
+func testGoldenFile(t *testing.T, pascalFile string, goldenFile string, parserOpts []parser.Option, opts ...Option) {
+	writeMode := os.Getenv("UPDATE_GOLDEN") == "1"
 
-			goldenFileContent, err := ioutil.ReadFile(goldenFile)
-			if err != nil {
-				writeMode = true
-			}
+	fileContent, err := ioutil.ReadFile(pascalFile)
+	require.NoError(t, err)
+
+	goldenFileContent, err := ioutil.ReadFile(goldenFile)
+	if err != nil {
+		writeMode = true
+	}
+
+	ast, err := parser.Parse(pascalFile, string(fileContent), parserOpts...)
+	require.NoError(t, err, "parsing source file failed")
 
-			ast, err := parser.Parse(pascalFile, string(fileContent))
-			require.NoError(t, err, "parsing source file failed")
+	//fmt.Printf("ast = %s\n", spew.Sdump(ast))
 
-			//fmt.Printf("ast = %s\n", spew.Sdump(ast))
+	goSource, err := Transpile(ast, opts...)
+	require.NoError(t, err, "transpile failed")
 
-			goSource, err := Transpile(ast)
-			require.NoError(t, err, "transpile failed")
+	if writeMode {
+		t.Logf("Writing transpiler output to golden file %s", goldenFile)
+		require.NoError(t, ioutil.WriteFile(goldenFile, []byte(goSource), 0644))
+	} else {
+		require.Equal(t, string(goldenFileContent), goSource, "transpiler output doesn't match golden file")
+	}
+}
+
+func TestTranspileNonAddressableVarParam(t *testing.T) {
+	code := `program test;
+
+	var x : integer;
+
+	function double(n : integer) : integer;
+	begin
+		double := 2 * n
+	end;
+
+	procedure addto(var target : integer; amount : integer);
+	begin
+		target := target + amount
+	end;
+
+	begin
+		x := double(1);
+		addto(x, 1)
+	end.`
+
+	ast, err := parser.Parse("test.pas", code)
+	require.NoError(t, err)
+
+	// the parser rejects non-variables as actual parameters for variable parameters,
+	// so the AST needs to be modified to make the transpiler encounter one.
+	funcCall := ast.Block.Statements[0].(*parser.AssignmentStatement).RightExpr
+	procCall := ast.Block.Statements[1].(*parser.ProcedureCallStatement)
+	procCall.ActualParams[0] = funcCall
+
+	_, err = Transpile(ast)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "actual parameter 1 for variable parameter target is not addressable")
+}
+
+func TestTranspileBuiltinOverride(t *testing.T) {
+	code := `program test;
+
+	var x : integer;
+
+	begin
+		x := 42;
+		readln(x);
+		inc(x);
+		writeln('x = ', x);
+		write(x);
+		writeln
+	end.`
+
+	ast, err := parser.Parse("test.pas", code)
+	require.NoError(t, err)
 
-			if writeMode {
-				t.Logf("Writing transpiler output to missing golden file %s", goldenFile)
-				require.NoError(t, ioutil.WriteFile(goldenFile, []byte(goSource), 0644))
-			} else {
-				require.Equal(t, string(goldenFileContent), goSource, "transpiler output doesn't match golden file")
+	logCall := func(stmt *parser.ProcedureCallStatement, expr func(parser.Expression) string) string {
+		var args []string
+		for _, param := range stmt.ActualParams {
+			args = append(args, expr(param))
+		}
+		return "log.Println(" + strings.Join(args, ", ") + ")"
+	}
+
+	output, err := Transpile(ast, WithBuiltinOverride("writeln", logCall, "log"))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{`"github.com/akrennmair/pascal/pas2go/system"`, `"log"`}, importPaths(t, output))
+	require.Contains(t, output, `log.Println("x = ", x)`)
+	require.Contains(t, output, "log.Println()")
+	require.Contains(t, output, "system.Write(x)")
+	require.Contains(t, output, "x++")
+	require.NotContains(t, output, "system.Writeln")
+
+	output, err = Transpile(ast, WithBuiltinOverride("inc", func(stmt *parser.ProcedureCallStatement, expr func(parser.Expression) string) string {
+		return expr(stmt.ActualParams[0]) + " += 2"
+	}))
+	require.NoError(t, err)
+
+	require.Contains(t, output, "x += 2")
+	require.Contains(t, output, `system.Writeln("x = ", x)`)
+
+	// imports of overrides of builtin procedures that aren't called are omitted.
+	output, err = Transpile(ast, WithBuiltinOverride("dispose", logCall, "log"))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{`"github.com/akrennmair/pascal/pas2go/system"`}, importPaths(t, output))
+
+	output, err = Transpile(ast, WithBuiltinOverride("readln", logCall, "log"))
+	require.NoError(t, err)
+
+	require.Contains(t, output, "log.Println(x)")
+
+	// only builtin procedures can be overridden.
+	_, err = Transpile(ast, WithBuiltinOverride("abs", logCall))
+	require.EqualError(t, err, "can't override abs, as it is not a builtin procedure")
+}
+
+// importPaths returns the import paths of the Go source code.
+func importPaths(t *testing.T, goCode string) []string {
+	f, err := goparser.ParseFile(token.NewFileSet(), "main.go", goCode, goparser.ImportsOnly)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, imp := range f.Imports {
+		paths = append(paths, imp.Path.Value)
+	}
+	return paths
+}
+
+func TestTranspileConcurrently(t *testing.T) {
+	code := `program test;
+
+	var p : ^integer;
+
+	begin
+		new(p);
+		p^ := 42;
+		writeln(p^)
+	end.`
+
+	ast, err := parser.Parse("test.pas", code)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		checked := i%2 == 0
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var opts []Option
+			if checked {
+				opts = append(opts, WithCheckedPointers())
 			}
-		})
+
+			output, err := Transpile(ast, opts...)
+			require.NoError(t, err)
+			require.Equal(t, checked, strings.Contains(output, "system.Deref"))
+		}()
 	}
+	wg.Wait()
 }
@@ -0,0 +1,125 @@
+//go:build integration
+
+package run_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akrennmair/pascal/parser"
+	"github.com/akrennmair/pascal/pas2go"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunPrograms transpiles every Pascal program in testdata, runs the generated Go
+// code and compares its output to the expected output in the corresponding .out file.
+// If a corresponding .in file exists, it is provided to the program as standard input.
+func TestRunPrograms(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.pas")
+	require.NoError(t, err)
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			source, err := os.ReadFile(file)
+			require.NoError(t, err, "reading %s failed", file)
+
+			ast, err := parser.Parse(file, string(source))
+			require.NoError(t, err, "parsing %s failed", file)
+
+			goCode, err := pas2go.Transpile(ast)
+			require.NoError(t, err, "transpiling %s failed", file)
+
+			expectedOutput, err := os.ReadFile(strings.TrimSuffix(file, ".pas") + ".out")
+			require.NoError(t, err, "reading expected output for %s failed", file)
+
+			var input []byte
+			if in, err := os.ReadFile(strings.TrimSuffix(file, ".pas") + ".in"); err == nil {
+				input = in
+			}
+
+			stdout, stderr, err := runGoCode(t, goCode, input)
+			require.NoError(t, err, "running %s failed: %s", file, stderr)
+			require.Equal(t, string(expectedOutput), stdout, "output of %s doesn't match", file)
+		})
+	}
+}
+
+// TestRunRangeChecks checks that programs transpiled with range checks fail when
+// an out-of-range value is read into a subrange variable.
+func TestRunRangeChecks(t *testing.T) {
+	ast, err := parser.Parse("rangecheck.pas", `program rangecheck(input, output);
+
+	var n : 1..10;
+
+	begin
+		readln(n);
+		writeln('n = ', n)
+	end.`)
+	require.NoError(t, err)
+
+	goCode, err := pas2go.Transpile(ast, pas2go.WithRangeChecks())
+	require.NoError(t, err)
+
+	stdout, stderr, err := runGoCode(t, goCode, []byte("7\n"))
+	require.NoError(t, err, "running program failed: %s", stderr)
+	require.Equal(t, "n = 7\n", stdout)
+
+	stdout, stderr, err = runGoCode(t, goCode, []byte("11\n"))
+	require.Error(t, err)
+	require.Empty(t, stdout)
+	require.Contains(t, stderr, "range error: 11 is not within 1..10")
+}
+
+// TestRunBuiltinOverride checks that programs transpiled with an override of a builtin
+// procedure import the packages that the override refers to.
+func TestRunBuiltinOverride(t *testing.T) {
+	ast, err := parser.Parse("override.pas", `program override(output);
+
+	var x : integer;
+
+	begin
+		x := 42;
+		writeln('x =', x, 'and', x * 2 + 1)
+	end.`)
+	require.NoError(t, err)
+
+	printCall := func(stmt *parser.ProcedureCallStatement, expr func(parser.Expression) string) string {
+		var args []string
+		for _, param := range stmt.ActualParams {
+			args = append(args, expr(param))
+		}
+		return "fmt.Println(" + strings.Join(args, ", ") + ")"
+	}
+
+	goCode, err := pas2go.Transpile(ast, pas2go.WithBuiltinOverride("writeln", printCall, "fmt"))
+	require.NoError(t, err)
+
+	stdout, stderr, err := runGoCode(t, goCode, nil)
+	require.NoError(t, err, "running program failed: %s", stderr)
+	require.Equal(t, "x = 42 and 85\n", stdout)
+}
+
+// runGoCode runs the Go source code with the provided standard input, and returns what it
+// wrote to standard output and standard error.
+func runGoCode(t *testing.T, goCode string, input []byte) (stdout string, stderr string, err error) {
+	// the generated code needs to be within this module so that it can import the system package.
+	dir, err := os.MkdirTemp(".", "run")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	goFile := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(goFile, []byte(goCode), 0644))
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	cmd := exec.Command("go", "run", "./"+goFile)
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+	cmd.Stdin = bytes.NewReader(input)
+
+	err = cmd.Run()
+	return stdoutBuf.String(), stderrBuf.String(), err
+}
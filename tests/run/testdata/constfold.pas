@@ -0,0 +1,20 @@
+program constfold;
+
+const a = -7 mod 3;
+	b = -7 div 2;
+	c = 2 - 7 mod 3;
+	d = -2 * 3 + 10 div 4;
+
+var seven, three, two, ten, four : integer;
+
+begin
+	seven := 7;
+	three := 3;
+	two := 2;
+	ten := 10;
+	four := 4;
+	writeln('a = ', a, ', runtime = ', -seven mod three);
+	writeln('b = ', b, ', runtime = ', -seven div two);
+	writeln('c = ', c, ', runtime = ', two - seven mod three);
+	writeln('d = ', d, ', runtime = ', -two * three + ten div four)
+end.
@@ -0,0 +1,5 @@
+program hello(output);
+
+begin
+	writeln('Hello, world!')
+end.
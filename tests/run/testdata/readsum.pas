@@ -0,0 +1,13 @@
+program readsum(input, output);
+
+var n, sum : integer;
+
+begin
+	sum := 0;
+	while not eof(input) do
+	begin
+		readln(n);
+		sum := sum + n
+	end;
+	writeln('sum = ', sum)
+end.
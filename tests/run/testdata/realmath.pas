@@ -0,0 +1,13 @@
+program realmath(output);
+
+var r : real;
+	i : integer;
+
+begin
+	r := 0.0;
+	for i := 1 to 4 do
+		r := r + 1.0 / i;
+	writeln(r:8:4);
+	writeln(trunc(r), ', ', round(r * 10));
+	writeln(sqr(3), ', ', sqrt(16.0):4:1, ', ', abs(-7))
+end.
@@ -0,0 +1,40 @@
+program recordfile;
+
+type
+	person = record
+		id : integer;
+		height : real;
+		initial : char
+	end;
+
+var
+	f : file of person;
+	p : person;
+	i : integer;
+
+begin
+	rewrite(f);
+	for i := 1 to 5 do
+	begin
+		p.id := i;
+		p.height := 0.5 * i;
+		p.initial := chr(ord('a') + i);
+		write(f, p)
+	end;
+
+	writeln('filesize = ', filesize(f));
+	writeln('filepos = ', filepos(f));
+
+	reset(f);
+	seek(f, 3);
+	read(f, p);
+	writeln('id = ', p.id, ', height = ', p.height, ', initial = ', p.initial);
+	writeln('filepos = ', filepos(f));
+
+	p.id := 42;
+	seek(f, 1);
+	write(f, p);
+	seek(f, 1);
+	read(f, p);
+	writeln('id = ', p.id, ', eof = ', eof(f))
+end.
@@ -0,0 +1,34 @@
+program records(output);
+
+type point = record
+		x, y : integer
+	end;
+	list = ^node;
+	node = record
+		p : point;
+		next : list
+	end;
+
+var head, n : list;
+	i, sum : integer;
+
+begin
+	head := nil;
+	for i := 1 to 5 do
+	begin
+		new(n);
+		n^.p.x := i;
+		n^.p.y := i * i;
+		n^.next := head;
+		head := n
+	end;
+	sum := 0;
+	n := head;
+	while n <> nil do
+	begin
+		with n^.p do
+			sum := sum + x * y;
+		n := n^.next
+	end;
+	writeln('sum = ', sum)
+end.
@@ -0,0 +1,25 @@
+program sets(output);
+
+type colour = (red, green, blue, yellow);
+
+var primary, seen : set of colour;
+	c : colour;
+	n : integer;
+
+begin
+	primary := [red, green, blue];
+	seen := [green, yellow];
+	n := 0;
+	for c := red to yellow do
+		if c in primary * seen then
+			n := n + 1;
+	writeln('common: ', n);
+	if [red] <= primary then
+		writeln('red is primary');
+	if not (yellow in primary) then
+		writeln('yellow is not primary');
+	seen := seen + [red] - [green];
+	for c := red to yellow do
+		if c in seen then
+			writeln('seen: ', ord(c))
+end.
@@ -0,0 +1,18 @@
+program stringtruncate(output);
+
+var s : string[5];
+	t : string;
+	u : string[3];
+
+begin
+	s := 'hello world';
+	writeln(s, '!!');
+	t := 'abcdefgh';
+	s := t;
+	writeln(s, '!!');
+	u := 'xy';
+	s := u;
+	writeln(s, '!!');
+	s := 'abc';
+	writeln(s, '!!')
+end.
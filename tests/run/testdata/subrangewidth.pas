@@ -0,0 +1,14 @@
+program subrangewidth(output);
+
+type offset = 1..10;
+	letter = 'a'..'z';
+
+var o : offset;
+	l : letter;
+
+begin
+	o := 5;
+	l := 'q';
+	writeln(o:4, l:3);
+	writeln('o = ', o:2)
+end.